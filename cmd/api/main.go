@@ -1,8 +1,15 @@
 // @title Song Library API
 // @version 1.0
 // @description API для работы с музыкальной библиотекой
+// @description Ошибки возвращаются в формате application/problem+json (RFC 7807), поле code содержит стабильный код ошибки
+// @description Язык сообщений об ошибках выбирается по заголовку Accept-Language (ru или en, по умолчанию ru) и указывается в Content-Language
 // @host localhost:8080
 // @BasePath /api
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description API-ключ или JWT в формате "Bearer <ключ>". API-ключ также принимается в заголовке X-API-Key.
+// @description Роли: viewer - чтение, editor - создание и изменение, admin - удаление, импорт и объединение
 package main
 
 import (
@@ -18,6 +25,7 @@ import (
 	"song-library/internal/config"
 	"song-library/internal/constants"
 	_ "song-library/internal/handlers"
+	"song-library/internal/i18n"
 	"song-library/internal/utils"
 
 	"github.com/joho/godotenv"
@@ -25,7 +33,7 @@ import (
 
 func main() {
 	// Добавляем логгер с временными метками
-	logger := log.New(os.Stdout, "", log.LstdFlags|log.Lshortfile)
+	logger := i18n.NewLogger(log.New(os.Stdout, "", log.LstdFlags|log.Lshortfile))
 
 	// Получаем путь к корню проекта
 	projectRoot := utils.GetProjectRoot(0)
@@ -40,6 +48,25 @@ func main() {
 		logger.Printf(constants.LogError, constants.ErrLoadingConfig, err)
 		logger.Fatal(constants.ErrInvalidData)
 	}
+	logger.SetLanguage(cfg.LogLanguage)
+
+	// Подкоманда массового импорта: api import [-format csv|ndjson] [-dry-run] <файл|->
+	if len(os.Args) > 1 && os.Args[1] == constants.CommandImport {
+		if err := app.RunImport(cfg, logger, os.Args[2:]); err != nil {
+			logger.Printf(constants.LogError, constants.ErrImportFailed, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Управление API-ключами: api apikey create <имя> | list | revoke <имя>
+	if len(os.Args) > 1 && os.Args[1] == constants.CommandAPIKey {
+		if err := app.RunAPIKey(cfg, os.Args[2:]); err != nil {
+			logger.Printf(constants.LogError, constants.ErrAPIKeyCommandFailed, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Создаем новый экземпляр приложения
 	app, err := app.NewApp(cfg, logger)
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"song-library/internal/auth"
+	"song-library/internal/config"
+	"song-library/internal/constants"
+	"song-library/internal/db"
+	"song-library/internal/i18n"
+	"song-library/internal/repository"
+)
+
+// RunAPIKey выполняет подкоманду apikey: create [-role роль] <имя> создает ключ и один раз
+// печатает его, list выводит ключи без секретов, revoke <имя> отзывает ключ.
+// Миграции должны быть уже применены
+func RunAPIKey(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(constants.ErrAPIKeyUsage)
+	}
+	command, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet(constants.CommandAPIKey, flag.ContinueOnError)
+	role := flags.String(constants.FlagRole, constants.RoleEditor, constants.UsageRole)
+	if command == constants.APIKeyCreate {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		args = flags.Args()
+		if !auth.ValidRole(*role) {
+			return i18n.Errorf(constants.ErrUnknownRole, *role, constants.APIKeyCreate)
+		}
+	}
+	if (command == constants.APIKeyList) != (len(args) == 0) || len(args) > 1 {
+		return errors.New(constants.ErrAPIKeyUsage)
+	}
+
+	database, err := db.NewDatabase(cfg.GetDBConnString())
+	if err != nil {
+		return i18n.Errorf(constants.ErrDBConnection, err)
+	}
+	defer database.Close()
+
+	repo, err := repository.NewAPIKeyRepository(database)
+	if err != nil {
+		return i18n.Errorf(constants.ErrFormat, constants.ErrAPIKeyRepoCreate, err)
+	}
+
+	switch command {
+	case constants.APIKeyCreate:
+		key, hash, prefix, err := auth.NewAPIKey()
+		if err != nil {
+			return err
+		}
+		created, err := repo.CreateAPIKey(args[0], hash, prefix, *role)
+		if err != nil {
+			return err
+		}
+		fmt.Printf(constants.LogAPIKeyCreated, created.Name, key)
+	case constants.APIKeyList:
+		keys, err := repo.ListAPIKeys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			fmt.Printf(constants.LogAPIKeyRow, key.Name, key.Prefix, key.Role,
+				key.CreatedAt.Format(constants.DateTimeFormat), formatOptionalTime(key.RevokedAt))
+		}
+	case constants.APIKeyRevoke:
+		if err := repo.RevokeAPIKey(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf(constants.LogAPIKeyRevoked, args[0])
+	default:
+		return errors.New(constants.ErrAPIKeyUsage)
+	}
+	return nil
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return constants.APIKeyActive
+	}
+	return t.Format(constants.DateTimeFormat)
+}
@@ -2,13 +2,12 @@ package app
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
 
 	"song-library/internal/config"
 	"song-library/internal/constants"
 	"song-library/internal/db"
+	"song-library/internal/i18n"
 	"song-library/internal/migrations"
 	"song-library/internal/server"
 )
@@ -17,13 +16,13 @@ type App struct {
 	cfg    *config.Config
 	db     *db.Database
 	server *http.Server
-	logger *log.Logger
+	logger *i18n.Logger
 }
 
-func NewApp(cfg *config.Config, logger *log.Logger) (*App, error) {
+func NewApp(cfg *config.Config, logger *i18n.Logger) (*App, error) {
 	database, err := db.NewDatabase(cfg.GetDBConnString())
 	if err != nil {
-		err = fmt.Errorf(constants.ErrDBConnection, err)
+		err = i18n.Errorf(constants.ErrDBConnection, err)
 		logger.Println(err)
 		return nil, err
 	}
@@ -39,7 +38,7 @@ func (a *App) Run(ctx context.Context) error {
 	// Инициализация и запуск миграций
 	migrator, err := migrations.NewMigrator(ctx, a.cfg.DB, a.logger)
 	if err != nil {
-		return fmt.Errorf(constants.ErrMigratorInit+constants.ErrFormatAddition, err)
+		return i18n.Errorf(constants.ErrMigratorInit+constants.ErrFormatAddition, err)
 	}
 	defer migrator.Close()
 
@@ -47,16 +46,24 @@ func (a *App) Run(ctx context.Context) error {
 		if downErr := migrator.Down(ctx); downErr != nil {
 			a.logger.Printf(constants.LogMigrationFailure, constants.ErrMigrationDown, downErr)
 		}
-		return fmt.Errorf(constants.ErrMigrationUp+constants.ErrFormatAddition, err)
+		return i18n.Errorf(constants.ErrMigrationUp+constants.ErrFormatAddition, err)
+	}
+
+	// Фоновая очистка корзины использует соединение приложения
+	songRepo, _, err := server.NewSongRepositories(a.cfg, a.db)
+	if err != nil {
+		return i18n.Errorf(constants.ErrServerSetup+constants.ErrFormatAddition, err)
 	}
 
 	// Используем существующую настройку сервера
 	server, err := server.Setup(a.cfg, a.logger)
 	if err != nil {
-		return fmt.Errorf(constants.ErrServerSetup+constants.ErrFormatAddition, err)
+		return i18n.Errorf(constants.ErrServerSetup+constants.ErrFormatAddition, err)
 	}
 	a.server = server
 
+	go a.purgeTrash(ctx, songRepo)
+
 	// Запускаем HTTP сервер в горутине
 	go func() {
 		a.logger.Printf(constants.LogServerStarted, a.server.Addr)
@@ -75,14 +82,14 @@ func (a *App) Shutdown(ctx context.Context) error {
 	if a.server != nil {
 		if err := a.server.Shutdown(ctx); err != nil {
 			a.logger.Printf(constants.LogError, constants.ErrGracefulShutdown, err)
-			return fmt.Errorf(constants.ErrGracefulShutdown+constants.ErrFormatAddition, err)
+			return i18n.Errorf(constants.ErrGracefulShutdown+constants.ErrFormatAddition, err)
 		}
 	}
 
 	if a.db != nil {
 		if err := a.db.Close(); err != nil {
 			a.logger.Printf(constants.LogError, constants.ErrDBConnection, err)
-			return fmt.Errorf(constants.ErrDBConnection, err)
+			return i18n.Errorf(constants.ErrDBConnection, err)
 		}
 	}
 
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"song-library/internal/audit"
+	"song-library/internal/config"
+	"song-library/internal/constants"
+	"song-library/internal/db"
+	"song-library/internal/i18n"
+	"song-library/internal/importer"
+	"song-library/internal/server"
+)
+
+// RunImport выполняет подкоманду import: загружает песни из файла (или stdin при "-")
+// и печатает отчет в формате JSON в stdout. Миграции должны быть уже применены
+func RunImport(cfg *config.Config, logger *i18n.Logger, args []string) error {
+	flags := flag.NewFlagSet(constants.CommandImport, flag.ContinueOnError)
+	format := flags.String(constants.FlagFormat, constants.ImportFormatCSV, constants.ImportFormatCSV+"|"+constants.ImportFormatNDJSON)
+	dryRun := flags.Bool(constants.FlagDryRun, false, constants.QueryParamDryRun)
+	actor := flags.String(constants.FlagActor, constants.CLIActor, constants.UsageActor)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New(constants.ErrImportUsage)
+	}
+
+	var input io.Reader = os.Stdin
+	if path := flags.Arg(0); path != constants.StdinFileName {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	database, err := db.NewDatabase(cfg.GetDBConnString())
+	if err != nil {
+		return i18n.Errorf(constants.ErrDBConnection, err)
+	}
+	defer database.Close()
+
+	songRepo, _, err := server.NewSongRepositories(cfg, database)
+	if err != nil {
+		return err
+	}
+
+	ctx := audit.WithActor(context.Background(), *actor)
+	report, err := importer.NewImporter(songRepo, logger).Import(ctx, input, importer.Options{
+		Format: *format,
+		DryRun: *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"song-library/internal/constants"
+	"song-library/internal/repository"
+)
+
+// purgeTrash сразу и затем каждые Trash.PurgeInterval окончательно удаляет песни,
+// пролежавшие в корзине дольше Trash.Retention. Завершается вместе с ctx
+func (a *App) purgeTrash(ctx context.Context, repo *repository.SongRepository) {
+	ticker := time.NewTicker(a.cfg.Trash.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := repo.PurgeTrash(a.cfg.Trash.Retention)
+		if err != nil {
+			a.logger.Printf(constants.LogError, constants.ErrPurgingTrash, err)
+		} else if purged > 0 {
+			a.logger.Printf(constants.LogTrashPurged, purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
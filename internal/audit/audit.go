@@ -0,0 +1,47 @@
+// Package audit передает автора изменения и ID запроса от HTTP-обработчиков
+// до репозиториев, которые записывают их в историю изменений
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"song-library/internal/constants"
+)
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// WithActor возвращает контекст с автором изменений
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// WithRequestID возвращает контекст с ID запроса
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// Actor возвращает автора изменений или пустую строку, если он неизвестен
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// RequestID возвращает ID запроса или пустую строку
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// NewRequestID формирует случайный ID запроса
+func NewRequestID() string {
+	b := make([]byte, constants.RequestIDBytes)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
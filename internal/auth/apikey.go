@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"song-library/internal/constants"
+)
+
+// NewAPIKey формирует случайный API-ключ и возвращает его вместе с хешем
+// для хранения и коротким префиксом для поиска ключа в списке
+func NewAPIKey() (key, hash, prefix string, err error) {
+	b := make([]byte, constants.APIKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	key = constants.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, HashAPIKey(key), key[:constants.APIKeyDisplayLength], nil
+}
+
+// HashAPIKey возвращает SHA-256 ключа в шестнадцатеричном виде. Ключ случайный
+// и длинный, поэтому соль и медленное хеширование не нужны
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey отличает API-ключ от JWT по префиксу
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, constants.APIKeyPrefix)
+}
@@ -0,0 +1,92 @@
+// Package auth проверяет API-ключи и JWT и передает аутентифицированного
+// клиента (Principal) в контексте запроса
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/models"
+	"song-library/internal/repository"
+)
+
+// ErrInvalidCredentials - ключ или токен передан, но не принят
+var ErrInvalidCredentials = errors.New(constants.ErrInvalidCredentials)
+
+// Principal - аутентифицированный клиент: имя API-ключа или subject токена и его роль
+type Principal struct {
+	Name   string
+	Method string
+	Role   string
+}
+
+type contextKey struct{}
+
+// WithPrincipal возвращает контекст с аутентифицированным клиентом
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext возвращает клиента запроса или nil для анонимного запроса
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
+
+// KeyStore ищет действующий API-ключ по хешу, для неизвестного или отозванного
+// ключа возвращает repository.ErrAPIKeyNotFound
+type KeyStore interface {
+	FindAPIKey(hash string) (*models.APIKey, error)
+}
+
+// Authenticator проверяет учетные данные запроса: API-ключ в заголовке X-API-Key
+// или Authorization: Bearer, либо JWT в Authorization: Bearer
+type Authenticator struct {
+	keys KeyStore
+	jwt  *JWTVerifier
+}
+
+// NewAuthenticator создает проверку учетных данных, jwt может быть nil - тогда
+// принимаются только API-ключи
+func NewAuthenticator(keys KeyStore, jwt *JWTVerifier) *Authenticator {
+	return &Authenticator{keys: keys, jwt: jwt}
+}
+
+// Authenticate возвращает клиента запроса. Для запроса без учетных данных
+// возвращается nil без ошибки, для непринятых - ErrInvalidCredentials
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	credential := r.Header.Get(constants.HeaderAPIKey)
+	if credential == "" {
+		header := r.Header.Get(constants.HeaderAuthorization)
+		if header == "" {
+			return nil, nil
+		}
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, constants.AuthSchemeBearer) || token == "" {
+			return nil, ErrInvalidCredentials
+		}
+		credential = strings.TrimSpace(token)
+	}
+
+	if IsAPIKey(credential) {
+		return a.authenticateKey(credential)
+	}
+	if a.jwt == nil {
+		return nil, ErrInvalidCredentials
+	}
+	return a.jwt.Verify(credential)
+}
+
+func (a *Authenticator) authenticateKey(key string) (*Principal, error) {
+	found, err := a.keys.FindAPIKey(HashAPIKey(key))
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{Name: found.Name, Method: constants.AuthMethodAPIKey, Role: found.Role}, nil
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"song-library/internal/constants"
+	"song-library/internal/models"
+	"song-library/internal/repository"
+)
+
+// fakeKeyStore хранит API-ключи в памяти по хешу и запоминает запрошенные хеши
+type fakeKeyStore struct {
+	keys   map[string]*models.APIKey
+	err    error
+	hashes []string
+}
+
+func (s *fakeKeyStore) FindAPIKey(hash string) (*models.APIKey, error) {
+	s.hashes = append(s.hashes, hash)
+	if s.err != nil {
+		return nil, s.err
+	}
+	if key, ok := s.keys[hash]; ok {
+		return key, nil
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+func TestNewAPIKey(t *testing.T) {
+	key, hash, prefix, err := NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+	if !IsAPIKey(key) || !strings.HasPrefix(key, prefix) || len(prefix) != constants.APIKeyDisplayLength {
+		t.Errorf("key = %q, prefix = %q", key, prefix)
+	}
+	if hash != HashAPIKey(key) || len(hash) != 64 {
+		t.Errorf("hash = %q, want HashAPIKey(key)", hash)
+	}
+	if strings.Contains(hash, key) {
+		t.Error("hash contains the key")
+	}
+
+	other, otherHash, _, err := NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+	if other == key || otherHash == hash {
+		t.Error("NewAPIKey() returned the same key twice")
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	key, hash, _, err := NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+	unknown, _, _, err := NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+	stored := &models.APIKey{Name: "ci", Role: constants.RoleEditor}
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		// wantErr - ожидаемая ошибка, nil вместе с wantName == "" - анонимный запрос
+		wantErr  error
+		wantName string
+	}{
+		{"заголовок X-API-Key", constants.HeaderAPIKey, key, nil, "ci"},
+		{"Authorization: Bearer", constants.HeaderAuthorization, constants.AuthSchemeBearer + " " + key, nil, "ci"},
+		{"схема без учета регистра", constants.HeaderAuthorization, "bearer " + key, nil, "ci"},
+		{"неизвестный ключ", constants.HeaderAPIKey, unknown, ErrInvalidCredentials, ""},
+		{"чужая схема", constants.HeaderAuthorization, "Basic " + key, ErrInvalidCredentials, ""},
+		{"пустой токен", constants.HeaderAuthorization, constants.AuthSchemeBearer + " ", ErrInvalidCredentials, ""},
+		{"JWT без настроенной проверки", constants.HeaderAuthorization, constants.AuthSchemeBearer + " a.b.c", ErrInvalidCredentials, ""},
+		{"без учетных данных", "", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeKeyStore{keys: map[string]*models.APIKey{hash: stored}}
+			r := httptest.NewRequest(http.MethodGet, "/api/songs", nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+
+			principal, err := NewAuthenticator(store, nil).Authenticate(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantName == "" {
+				if principal != nil {
+					t.Errorf("principal = %+v, want nil", principal)
+				}
+				return
+			}
+			if principal.Name != tt.wantName || principal.Role != constants.RoleEditor || principal.Method != constants.AuthMethodAPIKey {
+				t.Errorf("principal = %+v", principal)
+			}
+			// в хранилище ищется только хеш, сам ключ туда не передается
+			if len(store.hashes) != 1 || store.hashes[0] != hash {
+				t.Errorf("lookups = %v, want [%s]", store.hashes, hash)
+			}
+		})
+	}
+}
+
+// Сбой хранилища не выдается за неверный ключ, чтобы клиент получил 500, а не 401
+func TestAuthenticateKeyStoreError(t *testing.T) {
+	key, _, _, err := NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+	storeErr := errors.New("connection refused")
+	r := httptest.NewRequest(http.MethodGet, "/api/songs", nil)
+	r.Header.Set(constants.HeaderAPIKey, key)
+
+	_, err = NewAuthenticator(&fakeKeyStore{err: storeErr}, nil).Authenticate(r)
+	if !errors.Is(err, storeErr) || errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate() error = %v, want %v", err, storeErr)
+	}
+}
+
+// Токен без префикса API-ключа проверяется как JWT, а не ищется в хранилище
+func TestAuthenticateJWT(t *testing.T) {
+	store := &fakeKeyStore{}
+	token := makeToken(t, constants.JWTAlgHS256, claims(nil), signHS256(testSecret))
+	r := httptest.NewRequest(http.MethodGet, "/api/songs", nil)
+	r.Header.Set(constants.HeaderAuthorization, constants.AuthSchemeBearer+" "+token)
+
+	principal, err := NewAuthenticator(store, testVerifier(testSecret, nil)).Authenticate(r)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.Method != constants.AuthMethodJWT || len(store.hashes) != 0 {
+		t.Errorf("principal = %+v, lookups = %v", principal, store.hashes)
+	}
+}
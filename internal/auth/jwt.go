@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"song-library/internal/config"
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+)
+
+// JWTVerifier проверяет подпись HS256 или RS256 и стандартные утверждения токена
+type JWTVerifier struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+	issuer    string
+	audience  string
+	now       func() time.Time
+}
+
+// NewJWTVerifier создает проверку токенов по настройкам cfg. Если не задан ни общий
+// ключ, ни открытый ключ RSA, возвращается nil - токены не принимаются
+func NewJWTVerifier(cfg config.AuthConfig) (*JWTVerifier, error) {
+	if cfg.JWTSecret == "" && cfg.JWTPublicKeyFile == "" {
+		return nil, nil
+	}
+
+	v := &JWTVerifier{
+		secret:   []byte(cfg.JWTSecret),
+		issuer:   cfg.JWTIssuer,
+		audience: cfg.JWTAudience,
+		now:      time.Now,
+	}
+	if cfg.JWTPublicKeyFile != "" {
+		key, err := loadRSAPublicKey(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, i18n.Errorf(constants.ErrFormat, constants.ErrLoadingJWTKey, err)
+		}
+		v.publicKey = key
+	}
+	return v, nil
+}
+
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New(constants.ErrInvalidPEM)
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New(constants.ErrInvalidPEM)
+	}
+	return key, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+// jwtClaims - утверждения токена. Audience может быть строкой или массивом строк
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Role      string          `json:"role"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *int64          `json:"exp"`
+	NotBefore *int64          `json:"nbf"`
+}
+
+// Verify проверяет токен и возвращает клиента с именем из утверждения sub и ролью
+// из утверждения role, без role клиент получает роль viewer. Токен без срока
+// действия (exp) или с неизвестной ролью не принимается
+func (v *JWTVerifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidCredentials
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if !v.validSignature(header.Alg, parts[0]+"."+parts[1], signature) {
+		return nil, ErrInvalidCredentials
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	role := claims.Role
+	if role == "" {
+		role = constants.RoleViewer
+	}
+	if !ValidRole(role) {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Name: claims.Subject, Method: constants.AuthMethodJWT, Role: role}, nil
+}
+
+// validSignature проверяет подпись алгоритмом из заголовка, если для него настроен
+// ключ. Алгоритм none и прочие алгоритмы не принимаются
+func (v *JWTVerifier) validSignature(alg, signed string, signature []byte) bool {
+	switch alg {
+	case constants.JWTAlgHS256:
+		if len(v.secret) == 0 {
+			return false
+		}
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signed))
+		return hmac.Equal(signature, mac.Sum(nil))
+	case constants.JWTAlgRS256:
+		if v.publicKey == nil {
+			return false
+		}
+		digest := sha256.Sum256([]byte(signed))
+		return rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, digest[:], signature) == nil
+	default:
+		return false
+	}
+}
+
+func (v *JWTVerifier) validateClaims(claims jwtClaims) error {
+	now := v.now()
+	if claims.Subject == "" || claims.ExpiresAt == nil {
+		return ErrInvalidCredentials
+	}
+	if now.After(time.Unix(*claims.ExpiresAt, 0).Add(constants.JWTLeeway)) {
+		return ErrInvalidCredentials
+	}
+	if claims.NotBefore != nil && now.Add(constants.JWTLeeway).Before(time.Unix(*claims.NotBefore, 0)) {
+		return ErrInvalidCredentials
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return ErrInvalidCredentials
+	}
+	if v.audience != "" && !hasAudience(claims.Audience, v.audience) {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+func hasAudience(raw json.RawMessage, audience string) bool {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == audience
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return slices.Contains(list, audience)
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
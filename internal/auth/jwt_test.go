@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"song-library/internal/config"
+	"song-library/internal/constants"
+)
+
+var (
+	testSecret = []byte("secret")
+	testNow    = time.Unix(1_700_000_000, 0)
+)
+
+// testKey генерируется один раз, чтобы не замедлять тесты
+var testKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+func testVerifier(secret []byte, key *rsa.PublicKey) *JWTVerifier {
+	return &JWTVerifier{
+		secret:    secret,
+		publicKey: key,
+		issuer:    "song-library",
+		audience:  "api",
+		now:       func() time.Time { return testNow },
+	}
+}
+
+// makeToken собирает токен с заголовком alg и подписью sign от "заголовок.утверждения"
+func makeToken(t *testing.T, alg string, claims map[string]any, sign func(signed []byte) []byte) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func signHS256(secret []byte) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+func signRS256(key *rsa.PrivateKey) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			panic(err)
+		}
+		return signature
+	}
+}
+
+func noSignature([]byte) []byte { return nil }
+
+// claims возвращает действующие утверждения, измененные overrides; nil удаляет утверждение
+func claims(overrides map[string]any) map[string]any {
+	c := map[string]any{
+		"sub":  "ci",
+		"role": constants.RoleEditor,
+		"iss":  "song-library",
+		"aud":  "api",
+		"exp":  testNow.Add(time.Hour).Unix(),
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(c, name)
+			continue
+		}
+		c[name] = value
+	}
+	return c
+}
+
+func TestJWTVerify(t *testing.T) {
+	hs := testVerifier(testSecret, nil)
+	rs := testVerifier(nil, &testKey.PublicKey)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&testKey.PublicKey)})
+	leeway := constants.JWTLeeway
+
+	tests := []struct {
+		name     string
+		verifier *JWTVerifier
+		token    string
+		// wantRole - роль принятого токена, пустая строка - токен отклоняется
+		wantRole string
+	}{
+		{"HS256", hs, makeToken(t, constants.JWTAlgHS256, claims(nil), signHS256(testSecret)), constants.RoleEditor},
+		{"RS256", rs, makeToken(t, constants.JWTAlgRS256, claims(nil), signRS256(testKey)), constants.RoleEditor},
+		{"роль по умолчанию", hs, makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"role": nil}), signHS256(testSecret)), constants.RoleViewer},
+		{"неизвестная роль", hs, makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"role": "root"}), signHS256(testSecret)), ""},
+
+		{"alg none", hs, makeToken(t, "none", claims(nil), noSignature), ""},
+		{"alg None", hs, makeToken(t, "None", claims(nil), noSignature), ""},
+		{"неизвестный алгоритм", hs, makeToken(t, "HS512", claims(nil), signHS256(testSecret)), ""},
+		{"HS256 при ключе RS256", rs, makeToken(t, constants.JWTAlgHS256, claims(nil), signHS256(publicPEM)), ""},
+		{"RS256 при общем ключе", hs, makeToken(t, constants.JWTAlgRS256, claims(nil), signRS256(testKey)), ""},
+		{"чужой общий ключ", hs, makeToken(t, constants.JWTAlgHS256, claims(nil), signHS256([]byte("other"))), ""},
+		{"подпись другим ключом RSA", rs, makeToken(t, constants.JWTAlgRS256, claims(nil), signRS256(otherKey(t))), ""},
+		{"измененные утверждения", hs, tamper(t, makeToken(t, constants.JWTAlgHS256, claims(nil), signHS256(testSecret))), ""},
+		{"не три части", hs, "a.b", ""},
+		{"подпись не base64", hs, makeToken(t, constants.JWTAlgHS256, claims(nil), signHS256(testSecret)) + "!", ""},
+
+		{"без exp", hs, makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"exp": nil}), signHS256(testSecret)), ""},
+		{"без sub", hs, makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"sub": nil}), signHS256(testSecret)), ""},
+		{"истек в пределах допуска", hs, makeToken(t, constants.JWTAlgHS256,
+			claims(map[string]any{"exp": testNow.Add(-leeway + time.Second).Unix()}), signHS256(testSecret)), constants.RoleEditor},
+		{"истек", hs, makeToken(t, constants.JWTAlgHS256,
+			claims(map[string]any{"exp": testNow.Add(-leeway - time.Second).Unix()}), signHS256(testSecret)), ""},
+		{"nbf в пределах допуска", hs, makeToken(t, constants.JWTAlgHS256,
+			claims(map[string]any{"nbf": testNow.Add(leeway - time.Second).Unix()}), signHS256(testSecret)), constants.RoleEditor},
+		{"nbf в будущем", hs, makeToken(t, constants.JWTAlgHS256,
+			claims(map[string]any{"nbf": testNow.Add(leeway + time.Second).Unix()}), signHS256(testSecret)), ""},
+
+		{"чужой iss", hs, makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"iss": "other"}), signHS256(testSecret)), ""},
+		{"без iss", hs, makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"iss": nil}), signHS256(testSecret)), ""},
+		{"чужой aud", hs, makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"aud": "web"}), signHS256(testSecret)), ""},
+		{"aud массивом", hs, makeToken(t, constants.JWTAlgHS256,
+			claims(map[string]any{"aud": []string{"web", "api"}}), signHS256(testSecret)), constants.RoleEditor},
+		{"aud массивом без нашего", hs, makeToken(t, constants.JWTAlgHS256,
+			claims(map[string]any{"aud": []string{"web"}}), signHS256(testSecret)), ""},
+		{"aud числом", hs, makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"aud": 1}), signHS256(testSecret)), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := tt.verifier.Verify(tt.token)
+			if tt.wantRole == "" {
+				if !errors.Is(err, ErrInvalidCredentials) {
+					t.Fatalf("Verify() = %+v, %v, want ErrInvalidCredentials", principal, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if principal.Name != "ci" || principal.Role != tt.wantRole || principal.Method != constants.AuthMethodJWT {
+				t.Errorf("principal = %+v", principal)
+			}
+		})
+	}
+}
+
+// Без настроенных iss и aud эти утверждения не проверяются
+func TestJWTVerifyWithoutIssuerAudience(t *testing.T) {
+	v := testVerifier(testSecret, nil)
+	v.issuer, v.audience = "", ""
+
+	token := makeToken(t, constants.JWTAlgHS256, claims(map[string]any{"iss": "other", "aud": nil}), signHS256(testSecret))
+	if _, err := v.Verify(token); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestNewJWTVerifierPublicKeyFile(t *testing.T) {
+	der, err := x509.MarshalPKIXPublicKey(&testKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := NewJWTVerifier(config.AuthConfig{JWTPublicKeyFile: path})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() error = %v", err)
+	}
+	token := makeToken(t, constants.JWTAlgRS256, claims(map[string]any{"exp": time.Now().Add(time.Hour).Unix()}), signRS256(testKey))
+	if _, err := v.Verify(token); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	// общий ключ не задан, поэтому HS256 не принимается даже с пустой подписью
+	if _, err := v.Verify(makeToken(t, constants.JWTAlgHS256, claims(nil), signHS256(nil))); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Verify(HS256) error = %v, want ErrInvalidCredentials", err)
+	}
+
+	if v, err := NewJWTVerifier(config.AuthConfig{}); v != nil || err != nil {
+		t.Errorf("NewJWTVerifier(empty) = %v, %v, want nil, nil", v, err)
+	}
+	if _, err := NewJWTVerifier(config.AuthConfig{JWTPublicKeyFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("NewJWTVerifier(missing file) error = nil")
+	}
+}
+
+func otherKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// tamper заменяет утверждения токена, сохраняя заголовок и подпись
+func tamper(t *testing.T, token string) string {
+	t.Helper()
+	payload, err := json.Marshal(claims(map[string]any{"role": constants.RoleAdmin}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	return parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+}
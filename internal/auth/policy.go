@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+)
+
+// roleRank упорядочивает роли: каждая следующая роль включает права предыдущих
+var roleRank = map[string]int{
+	constants.RoleViewer: 1,
+	constants.RoleEditor: 2,
+	constants.RoleAdmin:  3,
+}
+
+// ValidRole сообщает, известна ли роль
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// HasRole сообщает, достаточно ли роли клиента для роли required
+func (p *Principal) HasRole(required string) bool {
+	return roleRank[p.Role] >= roleRank[required]
+}
+
+// Policy определяет роль, необходимую для маршрута. Роль ищется сначала по шаблону
+// маршрута ("POST /api/songs/import"), затем по HTTP-методу
+type Policy struct {
+	Methods map[string]string `json:"methods"`
+	Routes  map[string]string `json:"routes"`
+}
+
+// DefaultPolicy - политика по умолчанию: чтение доступно viewer, создание и изменение
+// editor, удаление, импорт и объединение дубликатов - admin. Удаление элемента
+// плейлиста - это изменение плейлиста, поэтому оно доступно editor
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Methods: map[string]string{
+			http.MethodGet:    constants.RoleViewer,
+			http.MethodHead:   constants.RoleViewer,
+			http.MethodPost:   constants.RoleEditor,
+			http.MethodPut:    constants.RoleEditor,
+			http.MethodPatch:  constants.RoleEditor,
+			http.MethodDelete: constants.RoleAdmin,
+		},
+		Routes: map[string]string{
+			routePattern(http.MethodPost, constants.APISongImportPath):     constants.RoleAdmin,
+			routePattern(http.MethodPost, constants.APISongMergePath):      constants.RoleAdmin,
+			routePattern(http.MethodDelete, constants.APIPlaylistItemPath): constants.RoleEditor,
+		},
+	}
+}
+
+// LoadPolicy читает политику из JSON-файла вида
+// {"methods": {"DELETE": "editor"}, "routes": {"POST /api/songs/import": "editor"}}.
+// Указанные в файле правила дополняют и переопределяют политику по умолчанию,
+// при пустом path возвращается политика по умолчанию
+func LoadPolicy(path string) (*Policy, error) {
+	policy := DefaultPolicy()
+	if path == "" {
+		return policy, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf(constants.ErrFormat, constants.ErrLoadingPolicy, err)
+	}
+	var file Policy
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, i18n.Errorf(constants.ErrFormat, constants.ErrLoadingPolicy, err)
+	}
+
+	for method, role := range file.Methods {
+		if !ValidRole(role) {
+			return nil, i18n.Errorf(constants.ErrUnknownRole, role, method)
+		}
+		policy.Methods[strings.ToUpper(method)] = role
+	}
+	for pattern, role := range file.Routes {
+		if !ValidRole(role) {
+			return nil, i18n.Errorf(constants.ErrUnknownRole, role, pattern)
+		}
+		policy.Routes[pattern] = role
+	}
+	return policy, nil
+}
+
+// Role возвращает роль, необходимую для маршрута method path. Для метода без
+// правила требуется admin
+func (p *Policy) Role(method, path string) string {
+	if role, ok := p.Routes[routePattern(method, path)]; ok {
+		return role
+	}
+	if role, ok := p.Methods[method]; ok {
+		return role
+	}
+	return constants.RoleAdmin
+}
+
+func routePattern(method, path string) string {
+	return fmt.Sprintf(constants.RouteFormat, method, path)
+}
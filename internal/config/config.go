@@ -2,14 +2,34 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"song-library/internal/constants"
+	"song-library/internal/i18n"
+
+	"golang.org/x/text/language"
 )
 
 type Config struct {
 	DB            DatabaseConfig
+	SongInfo      SongInfoConfig
+	Lyrics        LyricsConfig
+	Search        SearchConfig
+	Trash         TrashConfig
+	Auth          AuthConfig
+	RateLimit     RateLimitConfig
 	ServerAddress string
+	// RequireIfMatch требует заголовок If-Match для изменения и удаления песен
+	RequireIfMatch bool
+	// UniqueSongKey запрещает дубликаты песен уникальным индексом по нормализованным
+	// названию и исполнителю, индекс создается или удаляется при запуске
+	UniqueSongKey bool
+	// LogLanguage - язык сообщений журнала сервера
+	LogLanguage language.Tag
 }
 
 type DatabaseConfig struct {
@@ -21,6 +41,69 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+// SongInfoConfig описывает подключение к внешнему сервису информации о песнях
+type SongInfoConfig struct {
+	BaseURL    string
+	Timeout    time.Duration
+	Retries    int
+	AuthHeader string
+	AuthToken  string
+}
+
+// LyricsConfig управляет разбиением текста песни на куплеты
+type LyricsConfig struct {
+	DetectChorus bool
+}
+
+// SearchConfig управляет нечетким поиском по названию, исполнителю и альбому
+type SearchConfig struct {
+	// FuzzyThreshold - порог pg_trgm.word_similarity_threshold от 0 до 1
+	FuzzyThreshold float64
+}
+
+// TrashConfig управляет окончательным удалением песен из корзины
+type TrashConfig struct {
+	// Retention - сколько песня хранится в корзине до окончательного удаления
+	Retention time.Duration
+	// PurgeInterval - период запуска фоновой очистки корзины
+	PurgeInterval time.Duration
+}
+
+// AuthConfig управляет аутентификацией запросов к API
+type AuthConfig struct {
+	// PublicRead разрешает GET-запросы без учетных данных
+	PublicRead bool
+	// JWTSecret - общий ключ для токенов HS256
+	JWTSecret string
+	// JWTPublicKeyFile - путь к открытому ключу RSA в формате PEM для токенов RS256
+	JWTPublicKeyFile string
+	// JWTIssuer и JWTAudience, если заданы, сверяются с утверждениями iss и aud
+	JWTIssuer   string
+	JWTAudience string
+	// PolicyFile - путь к JSON-файлу с ролями для маршрутов, дополняющему политику по умолчанию
+	PolicyFile string
+}
+
+// RateLimit - скорость пополнения в запросах в секунду и запас запросов клиента.
+// Нулевая скорость отключает ограничение
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitConfig управляет ограничением частоты запросов по API-ключу или IP клиента
+type RateLimitConfig struct {
+	// IP применяется к каждому запросу по IP клиента до аутентификации и выбора
+	// маршрута, поэтому ограничивает и запросы с неверными учетными данными
+	IP RateLimit
+	// Default применяется к маршрутам без собственного лимита
+	Default RateLimit
+	// Routes - лимиты маршрутов по шаблону, например "GET /api/songs"
+	Routes map[string]RateLimit
+	// TrustedProxies - адреса прокси, которым доверяется заголовок X-Forwarded-For
+	TrustedProxies []netip.Prefix
+}
+
 func LoadConfig() (*Config, error) {
 	dbConfig := DatabaseConfig{}
 
@@ -58,17 +141,230 @@ func LoadConfig() (*Config, error) {
 	}
 	serverAddress := fmt.Sprintf(constants.DefaultAddressFormat, serverProtocol, serverHost, serverPort)
 
+	songInfoConfig, err := loadSongInfoConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	lyricsConfig := LyricsConfig{}
+	if lyricsConfig.DetectChorus, err = getBoolEnv(constants.EnvLyricsDetectChorus, false); err != nil {
+		return nil, err
+	}
+
+	searchConfig := SearchConfig{FuzzyThreshold: constants.DefaultFuzzyThreshold}
+	if value, ok := os.LookupEnv(constants.EnvFuzzyThreshold); ok {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			return nil, i18n.Errorf(constants.ErrInvalidEnvVar, constants.EnvFuzzyThreshold, value)
+		}
+		searchConfig.FuzzyThreshold = threshold
+	}
+
+	requireIfMatch, err := getBoolEnv(constants.EnvRequireIfMatch, false)
+	if err != nil {
+		return nil, err
+	}
+	uniqueSongKey, err := getBoolEnv(constants.EnvSongUniqueKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	trashConfig := TrashConfig{}
+	if trashConfig.Retention, err = getDurationEnv(constants.EnvTrashRetention, constants.DefaultTrashRetention); err != nil {
+		return nil, err
+	}
+	if trashConfig.PurgeInterval, err = getDurationEnv(constants.EnvTrashPurgeInterval, constants.DefaultTrashPurgeInterval); err != nil {
+		return nil, err
+	}
+
+	authConfig := AuthConfig{
+		JWTSecret:        getEnv(constants.EnvJWTSecret, ""),
+		JWTPublicKeyFile: getEnv(constants.EnvJWTPublicKeyFile, ""),
+		JWTIssuer:        getEnv(constants.EnvJWTIssuer, ""),
+		JWTAudience:      getEnv(constants.EnvJWTAudience, ""),
+		PolicyFile:       getEnv(constants.EnvAuthPolicyFile, ""),
+	}
+	if authConfig.PublicRead, err = getBoolEnv(constants.EnvAuthPublicRead, true); err != nil {
+		return nil, err
+	}
+
+	rateLimitConfig, err := loadRateLimitConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	logLanguage, err := i18n.Parse(getEnv(constants.EnvLogLanguage, constants.DefaultLogLanguage))
+	if err != nil {
+		return nil, i18n.Errorf(constants.ErrInvalidEnvVar, constants.EnvLogLanguage, err)
+	}
+
 	return &Config{
-		DB:            dbConfig,
-		ServerAddress: serverAddress,
+		DB:             dbConfig,
+		SongInfo:       songInfoConfig,
+		Lyrics:         lyricsConfig,
+		Search:         searchConfig,
+		Trash:          trashConfig,
+		Auth:           authConfig,
+		RateLimit:      rateLimitConfig,
+		ServerAddress:  serverAddress,
+		RequireIfMatch: requireIfMatch,
+		UniqueSongKey:  uniqueSongKey,
+		LogLanguage:    logLanguage,
 	}, nil
 }
 
+// loadSongInfoConfig читает настройки внешнего сервиса, все параметры необязательны
+func loadSongInfoConfig() (SongInfoConfig, error) {
+	cfg := SongInfoConfig{
+		BaseURL:    getEnv(constants.EnvSongInfoURL, ""),
+		Timeout:    constants.DefaultSongInfoTimeout,
+		Retries:    constants.DefaultSongInfoRetries,
+		AuthHeader: getEnv(constants.EnvSongInfoAuthHeader, constants.HeaderAuthorization),
+		AuthToken:  getEnv(constants.EnvSongInfoAuthToken, ""),
+	}
+
+	if value, ok := os.LookupEnv(constants.EnvSongInfoTimeout); ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return cfg, i18n.Errorf(constants.ErrInvalidEnvVar, constants.EnvSongInfoTimeout, value)
+		}
+		cfg.Timeout = timeout
+	}
+	if value, ok := os.LookupEnv(constants.EnvSongInfoRetries); ok {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return cfg, i18n.Errorf(constants.ErrInvalidEnvVar, constants.EnvSongInfoRetries, value)
+		}
+		cfg.Retries = retries
+	}
+
+	return cfg, nil
+}
+
+// loadRateLimitConfig читает лимиты запросов. Лимиты маршрутов задаются в виде
+// "GET /api/songs=5:10;POST /api/songs/import=0.1:1", где 5 - запросов в секунду, 10 - запас.
+// Шаблон маршрута без метода или вне /api/ - ошибка, а не молча пропущенный лимит
+func loadRateLimitConfig() (RateLimitConfig, error) {
+	cfg := RateLimitConfig{
+		IP:      RateLimit{Rate: constants.DefaultIPRateLimit, Burst: constants.DefaultIPRateLimitBurst},
+		Default: RateLimit{Rate: constants.DefaultRateLimit, Burst: constants.DefaultRateLimitBurst},
+		Routes:  make(map[string]RateLimit),
+	}
+
+	if err := lookupRateLimit(&cfg.IP, constants.EnvRateLimitIP, constants.EnvRateLimitIPBurst); err != nil {
+		return cfg, err
+	}
+	if err := lookupRateLimit(&cfg.Default, constants.EnvRateLimit, constants.EnvRateLimitBurst); err != nil {
+		return cfg, err
+	}
+
+	for _, entry := range splitList(getEnv(constants.EnvRateLimitRoutes, ""), constants.RateLimitRouteSeparator) {
+		pattern, spec, _ := strings.Cut(entry, constants.RateLimitAssign)
+		rateValue, burstValue, ok := strings.Cut(spec, constants.RateLimitBurstSeparator)
+		rate, rateErr := strconv.ParseFloat(rateValue, 64)
+		burst, burstErr := strconv.Atoi(burstValue)
+		if !ok || rateErr != nil || burstErr != nil || rate < 0 || burst < 1 {
+			return cfg, i18n.Errorf(constants.ErrInvalidEnvVar, constants.EnvRateLimitRoutes, entry)
+		}
+		pattern = strings.TrimSpace(pattern)
+		if !validRoutePattern(pattern) {
+			return cfg, i18n.Errorf(constants.ErrUnknownLimitRoute, pattern)
+		}
+		cfg.Routes[pattern] = RateLimit{Rate: rate, Burst: burst}
+	}
+
+	for _, entry := range splitList(getEnv(constants.EnvTrustedProxies, ""), constants.ListSeparator) {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(entry)
+			if addrErr != nil {
+				return cfg, i18n.Errorf(constants.ErrInvalidEnvVar, constants.EnvTrustedProxies, entry)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		cfg.TrustedProxies = append(cfg.TrustedProxies, prefix)
+	}
+
+	return cfg, nil
+}
+
+// lookupRateLimit переопределяет limit переменными окружения rateEnv и burstEnv, если они заданы
+func lookupRateLimit(limit *RateLimit, rateEnv, burstEnv string) error {
+	if value, ok := os.LookupEnv(rateEnv); ok {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			return i18n.Errorf(constants.ErrInvalidEnvVar, rateEnv, value)
+		}
+		limit.Rate = rate
+	}
+	if value, ok := os.LookupEnv(burstEnv); ok {
+		burst, err := strconv.Atoi(value)
+		if err != nil || burst < 1 {
+			return i18n.Errorf(constants.ErrInvalidEnvVar, burstEnv, value)
+		}
+		limit.Burst = burst
+	}
+	return nil
+}
+
+// validRoutePattern проверяет, что pattern - шаблон маршрута API с методом,
+// например "GET /api/songs/{id}". Зарегистрирован ли такой маршрут, проверяет
+// middleware.RateLimits.CheckRoutes при сборке маршрутов
+func validRoutePattern(pattern string) bool {
+	method, path, ok := strings.Cut(pattern, " ")
+	return ok && method != "" && method == strings.ToUpper(method) && strings.HasPrefix(path, constants.APINotFoundPath)
+}
+
+// splitList разбивает значение переменной по separator, пропуская пустые элементы
+func splitList(value, separator string) []string {
+	var items []string
+	for _, item := range strings.Split(value, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// возвращает значение переменной или значение по умолчанию
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
+
+// возвращает логическое значение переменной или значение по умолчанию
+func getBoolEnv(key string, defaultValue bool) (bool, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, i18n.Errorf(constants.ErrInvalidEnvVar, key, value)
+	}
+	return parsed, nil
+}
+
+// возвращает положительную длительность из переменной или значение по умолчанию
+func getDurationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return 0, i18n.Errorf(constants.ErrInvalidEnvVar, key, value)
+	}
+	return parsed, nil
+}
+
 // возвращает ошибку, если переменная не установлена
 func getRequiredEnv(key string) (string, error) {
 	value, exists := os.LookupEnv(key)
 	if !exists {
-		return "", fmt.Errorf(constants.ErrMissingEnvVar, key)
+		return "", i18n.Errorf(constants.ErrMissingEnvVar, key)
 	}
 	return value, nil
 }
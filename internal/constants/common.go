@@ -1,14 +1,67 @@
 package constants
 
+import "time"
+
 const (
 	DefaultFormat        = "%s%s"
 	DefaultAddressFormat = "%s://%s:%s"
 
 	ErrFormatAddition = ": %w"
 	ErrFormat         = "%s: %w"
+	ErrWrapFormat     = "%w: %v"
 
 	SQLExtension = ".sql"
 	SQLSuffix    = "_%s" + SQLExtension
+
+	// Динамические части SQL запросов
+	SQLSetPlaceholder     = "{{set}}"
+	SQLAssignFormat       = "%s = $%d"
+	SQLListSeparator      = ", "
+	SQLOrderByPlaceholder = "{{order_by}}"
+	SQLSortAsc            = " ASC"
+	SQLSortDesc           = " DESC"
+	SQLSortTiebreaker     = "s.id"
+	SQLSortSimilarity     = "s.similarity DESC"
+	SQLAfterPlaceholder   = "{{after}}"
+	SQLFragmentFormat     = "{{%s}}"
+	SQLTrue               = "TRUE"
+	SQLAnd                = " AND "
+	SQLOr                 = " OR "
+	SQLGroupFormat        = "(%s)"
+	SQLCompareFormat      = "%s %s $%d::%s"
+	SQLGreater            = ">"
+	SQLLess               = "<"
+	SQLEqual              = "="
+	SQLTypeText           = "text"
+	SQLTypeInt            = "int"
+	SQLTypeDate           = "date"
+	SQLTypeTimestamp      = "timestamp"
+	SQLZeroDate           = "0001-01-01"
+	SQLTimestampFormat    = "2006-01-02 15:04:05.999999"
+
+	// Колонки таблицы songs
+	ColumnTitle       = "title"
+	ColumnArtist      = "artist"
+	ColumnAlbum       = "album"
+	ColumnGenre       = "genre"
+	ColumnDuration    = "duration"
+	ColumnReleaseDate = "release_date"
+	ColumnText        = "text"
+	ColumnLink        = "link"
+	ColumnCreatedAt   = "created_at"
+	ColumnUpdatedAt   = "updated_at"
+	ColumnTrackNumber = "track_number"
+	ColumnDiscNumber  = "disc_number"
+	ColumnID          = "id"
+	ColumnVerses      = "verses"
+
+	// Сортировка списков: sort=artist,-release_date&order=asc
+	SortSeparator   = ","
+	SortDescPrefix  = "-"
+	SortOrderAsc    = "asc"
+	SortOrderDesc   = "desc"
+	DefaultSongSort = ColumnTitle
+	VerseSortKey    = "verse_number"
 	//БД
 	PostgresConnectionString = "postgres://%s:%s@%s:%s/%s?sslmode=%s"
 	PostgresDriver           = "postgres"
@@ -17,48 +70,194 @@ const (
 	APIBasePath   = "/api"
 	APISongsPath  = APIBasePath + "/songs"
 	APIVersesPath = APIBasePath + "/verses"
+	APISearchPath = APIBasePath + "/search"
 	MetricsPath   = "/metrics"
 
 	// Пути API для песен
-	APISongDelete = APISongsPath + "/delete"
-	APISongUpdate = APISongsPath + "/update"
-	APISongCreate = APISongsPath + "/create"
-	APISongInfo   = APISongsPath + "/info"
+	APISongPath           = APISongsPath + "/{id}"
+	APISongVersesPath     = APISongPath + "/verses"
+	APISongLyricsPath     = APISongPath + "/lyrics"
+	APISongInfo           = APISongsPath + "/info"
+	APISongDuplicatesPath = APISongsPath + "/duplicates"
+	APISongMergePath      = APISongPath + "/merge"
+	APISongRestorePath    = APISongPath + "/restore"
+	APISongTrashPath      = APISongsPath + "/trash"
+	APISongHistoryPath    = APISongPath + "/history"
+	APISongHistoryDiff    = APISongHistoryPath + "/diff"
+	APISongRevertPath     = APISongPath + "/revert/{" + PathParamRevision + "}"
+	APISongArtistsPath    = APISongPath + "/artists"
+	APISongImportPath     = APISongsPath + "/import"
+	APISongExportPath     = APISongsPath + "/export"
+
+	// Пути API справочников
+	APIArtistsPath     = APIBasePath + "/artists"
+	APIArtistPath      = APIArtistsPath + "/{id}"
+	APIArtistSongsPath = APIArtistPath + "/songs"
+	APIAlbumsPath      = APIBasePath + "/albums"
+	APIAlbumPath       = APIAlbumsPath + "/{id}"
+	APIAlbumSongsPath  = APIAlbumPath + "/songs"
+	APIGenresPath      = APIBasePath + "/genres"
+	APIGenrePath       = APIGenresPath + "/{id}"
+	APIGenreSongsPath  = APIGenrePath + "/songs"
+
+	// Пути API плейлистов
+	APIPlaylistsPath            = APIBasePath + "/playlists"
+	APIPlaylistPath             = APIPlaylistsPath + "/{id}"
+	APIPlaylistItemsPath        = APIPlaylistPath + "/items"
+	APIPlaylistItemPath         = APIPlaylistItemsPath + "/{" + PathParamItem + "}"
+	APIPlaylistItemPositionPath = APIPlaylistItemPath + "/position"
+
+	// Пути API для куплетов
+	APIVersePath         = APIVersesPath + "/{id}"
+	APIVersePositionPath = APIVersePath + "/position"
+
+	// Устаревшие пути API, сохраняются для совместимости на один релиз
+	APISongDelete  = APISongsPath + "/delete"
+	APISongUpdate  = APISongsPath + "/update"
+	APISongCreate  = APISongsPath + "/create"
+	APIVerseCreate = APIVersesPath + "/create"
+	APIVerseUpdate = APIVersesPath + "/update"
+	APIVerseMove   = APIVersesPath + "/move"
+	APIVerseDelete = APIVersesPath + "/delete"
+
+	// Маршруты с методами для http.ServeMux
+	RouteFormat = "%s %s"
+	// Все прочие пути API, для них отвечаем 404 или 405 в формате problem+json
+	APINotFoundPath = APIBasePath + "/"
+
+	// Параметры пути
+	PathParamID       = "id"
+	PathParamRevision = "rev"
+	PathParamItem     = "item"
 
 	// Пути
 	ProjectRootPath = "../.."
 
 	// Пути SQL
-	VerseQueriesPath = "queries/verses"
-	SongQueriesPath  = "queries/songs"
+	VerseQueriesPath    = "queries/verses"
+	SongQueriesPath     = "queries/songs"
+	SongFragmentsPath   = "queries/songs/fragments"
+	ArtistQueriesPath   = "queries/artists"
+	AlbumQueriesPath    = "queries/albums"
+	GenreQueriesPath    = "queries/genres"
+	PlaylistQueriesPath = "queries/playlists"
+	APIKeyQueriesPath   = "queries/api_keys"
 	// SQL Запросы на получение данных
-	QueryGet              = "get"
-	QueryCreateSong       = "create"
-	QueryCreateSimpleSong = "create_simple"
-	QueryUpdateSong       = "update"
-	QueryDeleteSong       = "delete"
-	QueryListSongs        = "list"
+	QueryGet                = "get"
+	QueryCreateSong         = "create"
+	QueryCreateSimpleSong   = "create_simple"
+	QueryUpdateSong         = "update"
+	QueryDeleteSong         = "delete"
+	QueryListSongs          = "list"
+	QueryPatchSong          = "patch"
+	QuerySongExists         = "exists"
+	QuerySearchSongs        = "search"
+	QueryListSongsFuzzy     = "list_fuzzy"
+	QuerySetSimilarity      = "set_similarity_threshold"
+	QueryCountSongs         = "count"
+	QueryListVersesAfter    = "list_after"
+	QueryGetVerse           = "get_by_id"
+	QueryCreateVerse        = "create"
+	QueryCreateVerses       = "create_batch"
+	QueryUpdateVerse        = "update"
+	QueryDeleteVerse        = "delete"
+	QueryVerseTypeID        = "get_type_id"
+	QueryLockSong           = "lock_song"
+	QueryNextVerseNumber    = "next_number"
+	QueryShiftVerses        = "shift"
+	QueryUnstashVerses      = "unstash"
+	QueryListVerseIDs       = "list_ids"
+	QueryReorderVerses      = "reorder"
+	QueryDeleteSongVerses   = "delete_by_song"
+	QuerySyncSongText       = "sync_song_text"
+	QueryList               = "list"
+	QueryUpdate             = "update"
+	QueryCatalogSongs       = "songs"
+	QuerySongCredits        = "credits"
+	QueryUpsertArtist       = "upsert"
+	QueryDeleteFeaturing    = "delete_featuring"
+	QueryAddFeaturing       = "add_featuring"
+	QueryImportBatch        = "import_batch"
+	QueryImportExisting     = "import_existing"
+	QuerySavepoint          = "savepoint"
+	QueryRollbackSavepoint  = "rollback_savepoint"
+	QueryReleaseSavepoint   = "release_savepoint"
+	QueryExportDeclare      = "export_declare"
+	QueryExportFetch        = "export_fetch"
+	QueryListVersesBySongs  = "list_by_songs"
+	QueryListTimings        = "list_timings"
+	QueryGetSongDuration    = "get_song_duration"
+	QueryCreateUniqueKey    = "create_unique_key"
+	QueryDropUniqueKey      = "drop_unique_key"
+	QueryDuplicates         = "duplicates"
+	QueryListSongsByIDs     = "list_by_ids"
+	QueryLockSongs          = "lock_songs"
+	QueryMergeFields        = "merge_fields"
+	QueryMergeVerses        = "merge_verses"
+	QueryMergeArtists       = "merge_artists"
+	QueryTrash              = "trash"
+	QueryRestoreSong        = "restore"
+	QueryPurgeTrash         = "purge"
+	QuerySetDeleteOperation = "set_delete_operation"
+	QueryRevisionCount      = "revision_count"
+	QueryHistory            = "history"
+	QueryGetRevision        = "get_revision"
+	QueryRevertSong         = "revert"
+	QueryMergePlaylists     = "merge_playlists"
+	QueryDeleteMergedSong   = "delete_merged"
+	QueryCreatePlaylist     = "create"
+	QueryDeletePlaylist     = "delete"
+	QueryLockPlaylist       = "lock"
+	QueryPlaylistItems      = "items"
+	QueryPlaylistItemIDs    = "item_ids"
+	QueryGetPlaylistItem    = "get_item"
+	QueryAddPlaylistItem    = "add_item"
+	QueryDeletePlaylistItem = "delete_item"
+	QueryShiftPlaylistItems = "shift"
+	QueryReorderPlaylist    = "reorder"
+	QueryPlaylistSongExists = "song_exists"
+	QueryCreateAPIKey       = "create"
+	QueryFindAPIKey         = "find"
+	QueryRevokeAPIKey       = "revoke"
+
+	// Типы куплетов
+	DefaultVerseType = "verse"
+	ChorusVerseType  = "chorus"
 
 	// Поля логов
-	LogFieldMethod   = "method"
-	LogFieldPath     = "path"
-	LogFieldStatus   = "status"
-	LogFieldDuration = "duration"
-	LogMsgRequest    = "Request processed"
+	LogFieldMethod    = "method"
+	LogFieldPath      = "path"
+	LogFieldStatus    = "status"
+	LogFieldDuration  = "duration"
+	LogFieldRequestID = "request_id"
+	LogFieldPrincipal = "principal"
+	LogMsgRequest     = "Request processed"
 
 	// Метрики
 	MetricHTTPRequestsTotal = "http_requests_total"
 	MetricHTTPRequestsHelp  = "Общее количество HTTP запросов"
+	MetricRateLimitedTotal  = "http_requests_rate_limited_total"
+	MetricRateLimitedHelp   = "Количество запросов, отклоненных ограничением частоты"
 
 	// Надписи для метрик
 	MetricLabelMethod   = "method"
 	MetricLabelEndpoint = "endpoint"
 	MetricLabelStatus   = "status"
+	MetricLabelClient   = "client"
+
+	// Значение метки endpoint для запросов, не сопоставленных маршруту
+	MetricEndpointUnmatched = "unmatched"
 
 	// Параметры URL запроса
 	QueryParamSongID   = "song_id"
+	QueryParamArtistID = "artist_id"
+	QueryParamFormat   = "format"
+	QueryParamDryRun   = "dry_run"
+	QueryParamVerses   = "verses"
 	QueryParamPage     = "page"
 	QueryParamPageSize = "page_size"
+	QueryParamFrom     = "from"
+	QueryParamTo       = "to"
 
 	// Значения по умолчанию
 	DefaultPage     = 1
@@ -66,32 +265,247 @@ const (
 	MaxPageSize     = 50
 
 	// Заголовки
-	HeaderContentType     = "Content-Type"
-	HeaderContentTypeJSON = "application/json"
-	HeaderCacheControl    = "Cache-Control"
-	CacheControlValue     = "public, max-age=300"
-
-	// Названия методов для обработчиков
-	HandlerGetVerses   = "GetVerses"
-	HandlerGetSongs    = "GetSongs"
-	HandlerDeleteSong  = "DeleteSong"
-	HandlerUpdateSong  = "UpdateSong"
-	HandlerCreateSong  = "CreateSong"
-	HandlerGetSongInfo = "GetSongInfo"
+	HeaderContentType        = "Content-Type"
+	HeaderContentTypeJSON    = "application/json"
+	ContentTypeProblem       = "application/problem+json"
+	ContentTypeMergePatch    = "application/merge-patch+json"
+	HeaderCacheControl       = "Cache-Control"
+	HeaderAuthorization      = "Authorization"
+	HeaderDeprecation        = "Deprecation"
+	HeaderETag               = "ETag"
+	HeaderIfMatch            = "If-Match"
+	ETagFormat               = `"%d"`
+	ETagWildcard             = "*"
+	ETagWeakPrefix           = "W/"
+	ETagSeparator            = ","
+	HeaderLink               = "Link"
+	HeaderNextCursor         = "X-Next-Cursor"
+	HeaderContentDisposition = "Content-Disposition"
+	HeaderRequestID          = "X-Request-ID"
+	HeaderAPIKey             = "X-API-Key"
+	HeaderWWWAuthenticate    = "WWW-Authenticate"
+	HeaderAllow              = "Allow"
+	HeaderForwardedFor       = "X-Forwarded-For"
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+	HeaderAcceptLanguage     = "Accept-Language"
+	HeaderContentLanguage    = "Content-Language"
+	HeaderVary               = "Vary"
+	AuthSchemeBearer         = "Bearer"
+	AuthChallenge            = `Bearer realm="song-library"`
+	DeprecationValue         = "true"
+	SuccessorLinkFormat      = "<%s>; rel=\"successor-version\""
+	CacheControlValue        = "public, max-age=300"
 
 	// Параметры URL запроса
-	QueryParamID      = "id"
-	QueryParamTitle   = "title"
-	QueryParamArtist  = "artist"
-	QueryParamGroup   = "group"
-	QueryParamSong    = "song"
-	QueryParamAlbum   = "album"
-	QueryParamGenre   = "genre"
-	QueryParamYear    = "year"
-	QueryParamPerPage = "per_page"
-
-	// Формат URL API
-	APIInfoURLFormat = APISongInfo + "?group=%s&song=%s"
+	QueryParamID       = "id"
+	QueryParamTitle    = "title"
+	QueryParamArtist   = "artist"
+	QueryParamGroup    = "group"
+	QueryParamSong     = "song"
+	QueryParamAlbum    = "album"
+	QueryParamGenre    = "genre"
+	QueryParamYear     = "year"
+	QueryParamPerPage  = "per_page"
+	QueryParamQuery    = "q"
+	QueryParamFuzzy    = "fuzzy"
+	QueryParamTranslit = "translit"
+	QueryParamSort     = "sort"
+	QueryParamOrder    = "order"
+	QueryParamCursor   = "cursor"
+	QueryParamTotal    = "include_total"
+
+	QueryParamYearFrom       = "year_from"
+	QueryParamYearTo         = "year_to"
+	QueryParamReleasedAfter  = "released_after"
+	QueryParamReleasedBefore = "released_before"
+	QueryParamDurationMin    = "duration_min"
+	QueryParamDurationMax    = "duration_max"
+	QueryParamHasText        = "has_text"
+	QueryParamHasLink        = "has_link"
+	QueryParamCreatedSince   = "created_since"
+	QueryParamUpdatedSince   = "updated_since"
+
+	// Массовый импорт песен
+	ImportFormatCSV      = "csv"
+	ImportFormatNDJSON   = "ndjson"
+	ContentTypeCSV       = "text/csv"
+	ContentTypeNDJSON    = "application/x-ndjson"
+	ImportStatusCreated  = "created"
+	ImportStatusSkipped  = "skipped"
+	ImportStatusFailed   = "failed"
+	ImportBatchSize      = 500
+	ImportMaxLineSize    = 1 << 20
+	ImportMaxBodySize    = 64 << 20
+	ImportTimeout        = 10 * time.Minute
+	CSVColumnGroup       = "group"
+	CSVColumnReleaseDate = "releaseDate"
+	UTF8BOM              = "\ufeff"
+
+	// Выгрузка песен
+	ExportFormatCSV          = ImportFormatCSV
+	ExportFormatNDJSON       = ImportFormatNDJSON
+	ExportFormatJSON         = "json"
+	ExportFileDateFormat     = "20060102"
+	ContentDispositionExport = "attachment; filename=\"songs-%s.%s\""
+	JSONArrayStart           = "["
+	JSONArraySeparator       = ",\n"
+	JSONArrayEnd             = "]\n"
+	ExportTimeout            = 10 * time.Minute
+
+	// Синхронизированный текст песни
+	LyricsFormatLRC   = "lrc"
+	LyricsFormatTXT   = "txt"
+	LyricsFormatSRT   = "srt"
+	LyricsMaxBodySize = 1 << 20
+	ContentTypeText   = "text/plain; charset=utf-8"
+	VerseSeparator    = "\n\n"
+	LRCLineFormat     = "[%s]%s\n"
+	LRCTimeFormat     = "%02d:%02d.%02d"
+	SRTTimeFormat     = "%02d:%02d:%02d,%03d"
+	SRTCueFormat      = "%d\n%s --> %s\n%s\n\n"
+
+	// Дубликаты песен
+	SongUniqueKeyIndex = "ux_songs_normalized_key"
+	PQUniqueViolation  = "23505"
+
+	// История изменений песен
+	RequestIDBytes     = 16
+	RequestIDMaxLength = 128
+	CLIActor           = "cli"
+	FieldTitle         = "title"
+	FieldArtist        = "artist"
+	FieldAlbum         = "album"
+	FieldGenre         = "genre"
+	FieldDuration      = "duration"
+	FieldReleaseDate   = "releaseDate"
+	FieldText          = "text"
+	FieldLink          = "link"
+	FieldTrackNumber   = "trackNumber"
+	FieldDiscNumber    = "discNumber"
+	DiffOpEqual        = " "
+	DiffOpInsert       = "+"
+	DiffOpDelete       = "-"
+	RevisionMerge      = "merge"
+
+	// Подкоманды командной строки
+	CommandImport = "import"
+	FlagFormat    = "format"
+	FlagDryRun    = "dry-run"
+	FlagActor     = "actor"
+	UsageActor    = "автор изменений в истории песен"
+	StdinFileName = "-"
+
+	// Аутентификация
+	CommandAPIKey       = "apikey"
+	APIKeyCreate        = "create"
+	APIKeyList          = "list"
+	APIKeyRevoke        = "revoke"
+	APIKeyPrefix        = "sl_"
+	APIKeyBytes         = 32
+	APIKeyDisplayLength = 11
+	AuthMethodAPIKey    = "api_key"
+	AuthMethodJWT       = "jwt"
+	RoleViewer          = "viewer"
+	RoleEditor          = "editor"
+	RoleAdmin           = "admin"
+	FlagRole            = "role"
+	UsageRole           = RoleViewer + "|" + RoleEditor + "|" + RoleAdmin
+	JWTAlgHS256         = "HS256"
+	JWTAlgRS256         = "RS256"
+	JWTLeeway           = 30 * time.Second
+	DateTimeFormat      = "2006-01-02 15:04:05"
+	APIKeyActive        = "active"
+
+	// Внешний сервис информации о песнях
+	SongInfoPath           = "info"
+	SongInfoDateFormat     = "02.01.2006"
+	DateFormat             = "2006-01-02"
+	DefaultSongInfoTimeout = 5 * time.Second
+	DefaultSongInfoRetries = 2
+	SongInfoRetryBackoff   = 200 * time.Millisecond
+
+	// Корзина песен
+	DefaultTrashRetention     = 30 * 24 * time.Hour
+	DefaultTrashPurgeInterval = time.Hour
+
+	// Ограничение частоты запросов
+	DefaultRateLimit        = 20.0
+	DefaultRateLimitBurst   = 40
+	DefaultIPRateLimit      = 50.0
+	DefaultIPRateLimitBurst = 100
+	RateLimitSweepInterval  = time.Minute
+	RateLimitRouteSeparator = ";"
+	RateLimitAssign         = "="
+	RateLimitBurstSeparator = ":"
+	ListSeparator           = ","
+	RateLimitKeyPrefix      = "key:"
+	RateLimitIPPrefix       = "ip:"
+	RateLimitClientKey      = "key"
+	RateLimitClientIP       = "ip"
+
+	// Локализация сообщений
+	LanguageRussian     = "ru"
+	LanguageEnglish     = "en"
+	DefaultLogLanguage  = LanguageRussian
+	ValidationSeparator = "; "
+
+	// Коды ошибок API (поле code ответа application/problem+json)
+	ProblemTypeFormat        = "urn:song-library:problem:%s"
+	ProblemCodeSeparator     = "_"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidID            = "invalid_id"
+	CodeInvalidJSON          = "invalid_json"
+	CodeInvalidContentType   = "invalid_content_type"
+	CodeSongNotFound         = "song_not_found"
+	CodeSongNotInTrash       = "song_not_in_trash"
+	CodeVerseNotFound        = "verse_not_found"
+	CodeArtistNotFound       = "artist_not_found"
+	CodeAlbumNotFound        = "album_not_found"
+	CodeGenreNotFound        = "genre_not_found"
+	CodeRevisionNotFound     = "revision_not_found"
+	CodePlaylistNotFound     = "playlist_not_found"
+	CodePlaylistItemNotFound = "playlist_item_not_found"
+	CodeAPIKeyNotFound       = "api_key_not_found"
+	CodeDuplicateSong        = "duplicate_song"
+	CodeDuplicatesExist      = "duplicates_exist"
+	CodeAPIKeyExists         = "api_key_exists"
+	CodeMergeSameSong        = "merge_same_song"
+	CodeVersionMismatch      = "version_mismatch"
+	CodePreconditionRequired = "precondition_required"
+	CodeInvalidCursor        = "invalid_cursor"
+	CodeUnknownVerseType     = "unknown_verse_type"
+	CodeInvalidPlaylistOrder = "invalid_playlist_order"
+	CodeInvalidTimings       = "invalid_timings"
+	CodeInvalidLRC           = "invalid_lrc"
+	CodeNoTimings            = "no_timings"
+	CodeInvalidImport        = "invalid_import"
+	CodeSongInfoNotFound     = "song_info_not_found"
+	CodeSongInfoTimeout      = "song_info_timeout"
+	CodeSongInfoMalformed    = "song_info_malformed"
+	CodeSongInfoUnavailable  = "song_info_unavailable"
+	CodeUnauthorized         = "unauthorized"
+	CodeInvalidCredentials   = "invalid_credentials"
+	CodeForbidden            = "forbidden"
+	CodeRateLimited          = "rate_limited"
+	CodeUnsupportedPatchType = "unsupported_patch_type"
+	CodeInternalError        = "internal_server_error"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	FieldName                = "name"
+	FieldSongID              = "song_id"
+	FieldPosition            = "position"
+	FieldContent             = "content"
+	FieldReleaseYear         = "release_year"
+	FieldDuplicateID         = "duplicate_id"
+	FieldVerseNumber         = "number"
+	FieldVerseType           = "type"
+
+	// Нечеткий поиск
+	DefaultFuzzyThreshold = 0.4
+	FuzzyThresholdFormat  = "%g"
 
 	// Environment variables
 	EnvDBHost         = "DB_HOST"
@@ -103,6 +517,31 @@ const (
 	EnvServerHost     = "SERVER_HOST"
 	EnvServerPort     = "SERVER_PORT"
 	EnvServerProtocol = "SERVER_PROTOCOL"
+
+	EnvSongInfoURL        = "SONG_INFO_API_URL"
+	EnvSongInfoTimeout    = "SONG_INFO_API_TIMEOUT"
+	EnvSongInfoRetries    = "SONG_INFO_API_RETRIES"
+	EnvSongInfoAuthHeader = "SONG_INFO_API_AUTH_HEADER"
+	EnvSongInfoAuthToken  = "SONG_INFO_API_AUTH_TOKEN"
+	EnvLyricsDetectChorus = "LYRICS_DETECT_CHORUS"
+	EnvRequireIfMatch     = "REQUIRE_IF_MATCH"
+	EnvSongUniqueKey      = "SONG_UNIQUE_KEY"
+	EnvTrashRetention     = "TRASH_RETENTION"
+	EnvTrashPurgeInterval = "TRASH_PURGE_INTERVAL"
+	EnvFuzzyThreshold     = "FUZZY_SIMILARITY_THRESHOLD"
+	EnvAuthPublicRead     = "AUTH_PUBLIC_READ"
+	EnvJWTSecret          = "AUTH_JWT_SECRET"
+	EnvJWTPublicKeyFile   = "AUTH_JWT_PUBLIC_KEY_FILE"
+	EnvJWTIssuer          = "AUTH_JWT_ISSUER"
+	EnvJWTAudience        = "AUTH_JWT_AUDIENCE"
+	EnvAuthPolicyFile     = "AUTH_POLICY_FILE"
+	EnvRateLimit          = "RATE_LIMIT_RPS"
+	EnvRateLimitBurst     = "RATE_LIMIT_BURST"
+	EnvRateLimitRoutes    = "RATE_LIMIT_ROUTES"
+	EnvRateLimitIP        = "RATE_LIMIT_IP_RPS"
+	EnvRateLimitIPBurst   = "RATE_LIMIT_IP_BURST"
+	EnvTrustedProxies     = "TRUSTED_PROXIES"
+	EnvLogLanguage        = "LOG_LANGUAGE"
 	// Configuration files
 	EnvFileName = ".env"
 
@@ -110,9 +549,5 @@ const (
 	SwaggerPath    = "/swagger/"
 	SwaggerDocPath = "/swagger/doc.json"
 
-	// Логи
-	LogInvalidContentType = "получен неверный Content-Type: %s"
-	ErrInvalidContentType = "неверный Content-Type, ожидается application/json"
-
 	DefaultProtocol = "http"
 )
@@ -1,23 +1,124 @@
 package constants
 
 const (
-	ErrMethodNotAllowed     = "метод не поддерживается"
 	ErrInvalidID            = "некорректный id"
 	ErrSongNotFound         = "песня не найдена"
+	ErrArtistNotFound       = "исполнитель не найден"
+	ErrAlbumNotFound        = "альбом не найден"
+	ErrGenreNotFound        = "жанр не найден"
+	ErrDuplicateSong        = "песня с таким названием и исполнителем уже есть в библиотеке"
+	ErrMergeSameSong        = "нельзя объединить песню саму с собой"
+	ErrDuplicatesExist      = "в библиотеке есть дубликаты, объедините их перед включением SONG_UNIQUE_KEY"
+	ErrInvalidDuplicateID   = "не указан ID дубликата"
+	ErrGettingDuplicates    = "ошибка при поиске дубликатов"
+	ErrMergingSongs         = "ошибка при объединении песен"
+	ErrUniqueKeySetup       = "ошибка настройки уникального ключа песен"
+	ErrSongNotInTrash       = "песня не найдена в корзине"
+	ErrGettingTrash         = "ошибка при получении корзины"
+	ErrRestoringSong        = "ошибка при восстановлении песни"
+	ErrPurgingTrash         = "ошибка очистки корзины"
+	ErrRevisionNotFound     = "ревизия песни не найдена"
+	ErrGettingHistory       = "ошибка получения истории изменений песни"
+	ErrRevertingSong        = "ошибка отката песни к ревизии"
+	ErrInvalidRevision      = "некорректный номер ревизии"
+	ErrPlaylistNotFound     = "плейлист не найден"
+	ErrPlaylistItemNotFound = "элемент плейлиста не найден"
+	ErrInvalidPlaylistOrder = "новый порядок должен содержать каждый элемент плейлиста ровно один раз"
+	ErrPlaylistNameRequired = "название плейлиста обязательно"
+	ErrInvalidPosition      = "позиция должна быть больше 0"
+	ErrInvalidSongID        = "не указан ID песни"
+	ErrGettingPlaylists     = "ошибка при получении плейлистов"
+	ErrSavingPlaylist       = "ошибка при сохранении плейлиста"
+	ErrDeletingPlaylist     = "ошибка при удалении плейлиста"
+	ErrChangingPlaylist     = "ошибка при изменении элементов плейлиста"
+	ErrAPIKeyNotFound       = "API-ключ не найден или отозван"
+	ErrAPIKeyExists         = "API-ключ с таким именем уже существует"
+	ErrAPIKeyCommandFailed  = "ошибка выполнения команды apikey"
+	ErrUnauthorized         = "требуется аутентификация"
+	ErrInvalidCredentials   = "недействительный API-ключ или токен"
+	ErrAuthenticating       = "ошибка проверки учетных данных"
+	ErrLoadingJWTKey        = "ошибка загрузки открытого ключа JWT"
+	ErrInvalidPEM           = "файл не содержит открытый ключ RSA в формате PEM"
+	ErrLoadingPolicy        = "ошибка загрузки политики доступа"
+	ErrUnknownRole          = "неизвестная роль %q для %s, ожидается viewer, editor или admin"
+	ErrForbidden            = "недостаточно прав: требуется роль %s"
+	ErrTooManyRequests      = "слишком много запросов, повторите позже"
+	ErrRouteNotFound        = "маршрут не найден"
+	ErrMethodNotAllowed     = "метод не поддерживается маршрутом"
+	ErrValidationFailed     = "ошибка валидации"
+	ErrUnknownLanguage      = "неподдерживаемый язык %q, ожидается ru или en"
+	ErrAPIKeyRepoCreate     = "ошибка создания api key repository"
+	ErrGettingCatalog       = "ошибка при получении справочника"
+	ErrInvalidReleaseYear   = "год выпуска должен быть больше 0"
+	ErrEmptyArtistName      = "имя исполнителя не может быть пустым"
 	ErrInvalidData          = "некорректные данные"
 	ErrRequiredFields       = "название и исполнитель обязательны"
+	ErrDurationRequired     = "длительность не может быть пустой или отрицательной"
+	ErrInvalidReleaseDate   = "дата выпуска должна быть в формате ГГГГ-ММ-ДД"
+	ErrPreconditionFailed   = "песня была изменена, получите актуальную версию и повторите запрос"
+	ErrPreconditionRequired = "для изменения песни требуется заголовок If-Match"
+	ErrInvalidPatchType     = "неверный Content-Type, ожидается application/merge-patch+json или application/json"
 	ErrGettingSongs         = "ошибка при получении списка песен"
+	ErrGettingSong          = "ошибка при получении песни"
+	ErrSearchingSongs       = "ошибка при поиске песен"
+	ErrSearchQueryRequired  = "поисковый запрос обязателен"
 	ErrDeletingSong         = "ошибка при удалении песни"
 	ErrUpdatingSong         = "ошибка при обновлении песни"
 	ErrCreatingSong         = "ошибка при создании песни"
 	ErrFetchingSongInfo     = "ошибка при получении информации о песне"
+	ErrSongInfoNotFound     = "информация о песне не найдена во внешнем сервисе"
+	ErrSongInfoTimeout      = "превышено время ожидания внешнего сервиса"
+	ErrSongInfoMalformed    = "некорректный ответ внешнего сервиса"
+	ErrSongInfoUnavailable  = "внешний сервис недоступен"
+	ErrSongInfoStatus       = "неожиданный статус ответа внешнего сервиса: %d"
 	ErrInvalidPage          = "страница должна быть больше 0"
+	ErrInvalidQueryParam    = "некорректное значение параметра %s"
 	ErrInvalidPerPage       = "количество элементов на странице должно быть от 1 до 100"
+	ErrInvalidSortField     = "сортировка по полю %s не поддерживается"
+	ErrInvalidSortOrder     = "порядок сортировки должен быть asc или desc"
+	ErrUnknownImportFormat  = "неизвестный формат импорта: %s"
+	ErrImportMissingColumn  = "в CSV отсутствует обязательная колонка %s"
+	ErrImportFieldCount     = "ожидалось %d значений, получено %d"
+	ErrImportRow            = "некорректная строка: %v"
+	ErrImportDuplicate      = "песня уже есть в библиотеке"
+	ErrImportDuplicateRow   = "песня повторяет строку %d"
+	ErrImportInvalidInput   = "некорректный файл импорта"
+	ErrImportFailed         = "ошибка импорта"
+	ErrImportBatch          = "ошибка сохранения пакета: %v"
+	ErrImportUsage          = "использование: import [-format csv|ndjson] [-dry-run] [-actor имя] <файл|->"
+	ErrAPIKeyUsage          = "использование: apikey create [-role viewer|editor|admin] <имя> | apikey list | apikey revoke <имя>"
+	ErrUnknownExportFormat  = "неизвестный формат выгрузки: %s"
+	ErrExportFuzzy          = "выгрузка не поддерживает нечеткий поиск"
+	ErrExportFailed         = "ошибка выгрузки песен"
+	ErrInvalidTimings       = "некорректные временные метки куплетов"
+	ErrTimingNegative       = "куплет %d: время не может быть отрицательным"
+	ErrTimingOrder          = "куплет %d: начинается раньше окончания предыдущего"
+	ErrTimingEnd            = "куплет %d: окончание должно быть позже начала"
+	ErrTimingDuration       = "куплет %d: время выходит за длительность песни"
+	ErrInvalidLRC           = "файл LRC не содержит строк с временными метками"
+	ErrNoTimings            = "у куплетов нет временных меток"
+	ErrNoStartTime          = "у куплета %d не задано время начала"
+	ErrNoEndTime            = "у куплета %d не задано время окончания"
+	ErrUnknownLyricsFormat  = "неизвестный формат текста: %s"
+	ErrGettingLyrics        = "ошибка при получении текста песни"
+	ErrUploadingLyrics      = "ошибка при загрузке текста песни"
+	ErrInvalidTrackNumber   = "номер трека и диска должен быть больше 0"
+	ErrInvalidRange         = "параметр %s не может быть больше %s"
+	ErrInvalidCursor        = "некорректный курсор"
+	ErrCursorWithFuzzy      = "курсорная пагинация недоступна для нечеткого поиска"
 	ErrDecodingJSON         = "ошибка декодирования json"
 	ErrEncodingResponse     = "ошибка кодирования ответа"
 	ErrProcessingSongInfo   = "ошибка при обработке информации о песне"
 	ErrSavingSong           = "ошибка при сохранении песни"
 	ErrGettingVerses        = "ошибка при получении куплетов"
+	ErrVerseNotFound        = "куплет не найден"
+	ErrUnknownVerseType     = "неизвестный тип куплета"
+	ErrVerseContentRequired = "текст куплета обязателен"
+	ErrInvalidVerseNumber   = "номер куплета должен быть больше 0"
+	ErrCreatingVerse        = "ошибка при создании куплета"
+	ErrUpdatingVerse        = "ошибка при обновлении куплета"
+	ErrMovingVerse          = "ошибка при перемещении куплета"
+	ErrDeletingVerse        = "ошибка при удалении куплета"
 	ErrLoadingConfig        = "ошибка загрузки конфигурации"
 	ErrServerSetup          = "ошибка настройки сервера"
 	ErrMigratorInit         = "ошибка инициализации мигратора"
@@ -26,6 +127,8 @@ const (
 	ErrServerCritical       = "критическая ошибка сервера"
 	ErrGracefulShutdown     = "ошибка при graceful shutdown"
 	ErrMissingEnvVar        = "отсутствует обязательная переменная окружения: %s"
+	ErrInvalidEnvVar        = "некорректное значение переменной окружения %s: %s"
+	ErrUnknownLimitRoute    = "неизвестный маршрут %q в RATE_LIMIT_ROUTES, ожидается шаблон вида \"GET /api/songs/{id}\""
 	ErrDBConnection         = "ошибка подключения к БД: %w"
 	ErrAppInit              = "ошибка инициализации приложения"
 	ErrAppRuntime           = "ошибка выполнения приложения"
@@ -44,32 +147,75 @@ const (
 	ErrReadingDirectory     = "ошибка чтения директории: %w"
 	ErrSongRepoCreate       = "ошибка создания song repository"
 	ErrVerseRepoCreate      = "ошибка создания verse repository"
+	ErrCatalogRepoCreate    = "ошибка создания catalog repository"
+	ErrPlaylistRepoCreate   = "ошибка создания playlist repository"
 
-	LogMethodNotAllowed  = "неверный метод %s для %s"
-	LogInvalidID         = "некорректный ID: %v"
-	LogSongNotFound      = "песня с ID %d не найдена"
-	LogValidationError   = "ошибка валидации фильтра: %v"
-	LogDecodingError     = "ошибка декодирования JSON: %v"
-	LogMissingFields     = "отсутствуют обязательные поля"
-	LogSuccessDelete     = "успешно удалена песня с ID %d"
-	LogSuccessUpdate     = "успешно обновлена песня с ID %d"
-	LogEncodingError     = "ошибка кодирования ответа: %v"
-	LogGettingVerses     = "ошибка при получении куплетов песни с ID %d: %v"
-	LogError             = "%s: %v"
-	LogConfigLoaded      = "Конфигурация загружена"
-	LogServerStarted     = "Сервер запущен на %s"
-	LogSignalReceived    = "Получен сигнал: %v"
-	LogShutdownNotice    = "Получено уведомление о завершении"
-	LogServerStopped     = "Сервер успешно остановлен"
-	LogMigrationRollback = "Миграции успешно откачены. Исходная ошибка: %v"
-	LogMigrationFailure  = "%v (исходная ошибка: %v)"
-	LogMigrationStart    = "Начало %s миграций..."
-	LogMigrationNotFound = "Файлы миграций не найдены"
-	LogMigrationProcess  = "%s миграции: %s"
-	LogMigrationPerTime  = "Миграции выполнена %s за %v"
-	LogDBConnected       = "Подключение к БД успешно установлено"
-	LogDBConnecting      = "Подключение к БД %s:%s..."
-	LogMigrationSkipped  = "миграция %s уже применена, данная версия пропущена"
-	LogReposInitialized  = "Репозитории успешно инициализированы"
-	LogServerSetupAddr   = "Настройка сервера на адресе: %s"
+	LogInvalidID               = "некорректный ID: %v"
+	LogSongNotFound            = "песня с ID %d не найдена"
+	LogValidationError         = "ошибка валидации фильтра: %v"
+	LogDecodingError           = "ошибка декодирования JSON: %v"
+	LogMissingFields           = "отсутствуют обязательные поля"
+	LogSuccessDelete           = "успешно удалена песня с ID %d"
+	LogSuccessUpdate           = "успешно обновлена песня с ID %d"
+	LogSuccessPatch            = "успешно частично обновлена песня с ID %d"
+	LogSuccessMerge            = "песня с ID %d объединена с дубликатом с ID %d"
+	LogSuccessRestore          = "песня с ID %d восстановлена из корзины"
+	LogSongNotInTrash          = "песня с ID %d не найдена в корзине"
+	LogTrashPurged             = "из корзины окончательно удалено песен: %d"
+	LogSuccessRevert           = "песня с ID %d возвращена к ревизии %d"
+	LogRevisionNotFound        = "ревизия %d песни с ID %d не найдена"
+	LogDuplicateSong           = "песня %q исполнителя %q уже есть в библиотеке"
+	LogVersionMismatch         = "версия песни с ID %d не совпала с If-Match"
+	LogIfMatchMissing          = "отсутствует заголовок If-Match для песни с ID %d"
+	LogEncodingError           = "ошибка кодирования ответа: %v"
+	LogDeadlineError           = "не удалось продлить срок обработки запроса: %v"
+	LogGettingVerses           = "ошибка при получении куплетов песни с ID %d: %v"
+	LogVerseNotFound           = "куплет с ID %d не найден"
+	LogImportFinished          = "импорт завершен: создано %d, пропущено %d, ошибок %d, пробный запуск: %t"
+	LogExportAborted           = "выгрузка прервана после начала ответа: %v"
+	LogSuccessAlbum            = "успешно обновлен альбом с ID %d"
+	LogSuccessFeaturing        = "обновлены приглашенные исполнители песни с ID %d"
+	LogSuccessCreateVerse      = "успешно создан куплет с ID %d для песни с ID %d"
+	LogSuccessUpdateVerse      = "успешно обновлен куплет с ID %d"
+	LogSuccessMoveVerse        = "куплет с ID %d перемещен на позицию %d"
+	LogSuccessDeleteVerse      = "успешно удален куплет с ID %d"
+	LogSuccessLyrics           = "загружен синхронизированный текст песни с ID %d, куплетов: %d"
+	LogSuccessPlaylist         = "сохранен плейлист с ID %d"
+	LogDeletedPlaylist         = "удален плейлист с ID %d"
+	LogPlaylistItemAdded       = "в плейлист с ID %d добавлена песня с ID %d на позицию %d"
+	LogPlaylistItemRemove      = "из плейлиста с ID %d удален элемент с ID %d"
+	LogPlaylistItemMoved       = "элемент с ID %d плейлиста с ID %d перемещен на позицию %d"
+	LogPlaylistReordered       = "изменен порядок элементов плейлиста с ID %d"
+	LogAPIKeyCreated           = "создан API-ключ %q, сохраните его - повторно он не показывается:\n%s\n"
+	LogAPIKeyRevoked           = "API-ключ %q отозван\n"
+	LogAPIKeyRow               = "%s\t%s\t%s\t%s\t%s\n"
+	LogAuthFailed              = "отклонены учетные данные для %s %s: %v"
+	LogAccessDenied            = "клиенту %q с ролью %s отказано в доступе к %s %s: требуется роль %s"
+	LogRateLimited             = "превышен лимит запросов клиентом %s для %s %s"
+	LogAuthDisabled            = "JWT не настроен, принимаются только API-ключи"
+	LogError                   = "%s: %v"
+	LogConfigLoaded            = "Конфигурация загружена"
+	LogServerStarted           = "Сервер запущен на %s"
+	LogSignalReceived          = "Получен сигнал: %v"
+	LogShutdownNotice          = "Получено уведомление о завершении"
+	LogServerStopped           = "Сервер успешно остановлен"
+	LogMigrationRollback       = "Миграции успешно откачены. Исходная ошибка: %v"
+	LogMigrationFailure        = "%v (исходная ошибка: %v)"
+	LogMigrationStart          = "Начало %s миграций..."
+	LogMigrationApply          = "применения"
+	LogMigrationRollbackAction = "отката"
+	LogMigrationApplied        = "применены"
+	LogMigrationRolledBack     = "отменены"
+	LogMigrationNotFound       = "Файлы миграций не найдены"
+	LogMigrationProcess        = "%s миграции: %s"
+	LogMigrationPerTime        = "Миграции выполнена %s за %v"
+	LogDBConnected             = "Подключение к БД успешно установлено"
+	LogDBConnecting            = "Подключение к БД %s:%s..."
+	LogMigrationSkipped        = "миграция %s уже применена, данная версия пропущена"
+	LogReposInitialized        = "Репозитории успешно инициализированы"
+	LogServerSetupAddr         = "Настройка сервера на адресе: %s"
+	LogSongInfoDisabled        = "Внешний сервис информации о песнях не настроен, обогащение отключено"
+	LogSongInfoRetry           = "повторный запрос к внешнему сервису (попытка %d): %v"
+	LogInvalidContentType      = "получен неверный Content-Type: %s"
+	ErrInvalidContentType      = "неверный Content-Type, ожидается application/json"
 )
@@ -0,0 +1,156 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+)
+
+// Encoder записывает песни выгрузки по одной, Close завершает документ
+type Encoder interface {
+	Write(song models.SongExport) error
+	Close() error
+}
+
+// NewEncoder создает Encoder для формата csv, ndjson или json. withVerses
+// добавляет в CSV колонку verses, в JSON куплеты выводятся при наличии
+func NewEncoder(format string, w io.Writer, withVerses bool) (Encoder, error) {
+	switch format {
+	case constants.ExportFormatCSV:
+		return &csvEncoder{writer: csv.NewWriter(w), withVerses: withVerses}, nil
+	case constants.ExportFormatNDJSON:
+		return &ndjsonEncoder{encoder: json.NewEncoder(w)}, nil
+	case constants.ExportFormatJSON:
+		return &jsonEncoder{w: w}, nil
+	default:
+		return nil, i18n.Errorf(constants.ErrUnknownExportFormat, format)
+	}
+}
+
+// ContentType возвращает MIME тип выгрузки в формате format
+func ContentType(format string) string {
+	switch format {
+	case constants.ExportFormatCSV:
+		return constants.ContentTypeCSV
+	case constants.ExportFormatNDJSON:
+		return constants.ContentTypeNDJSON
+	default:
+		return constants.HeaderContentTypeJSON
+	}
+}
+
+// csvColumns - колонки CSV выгрузки, совместимые с импортом
+var csvColumns = []string{
+	constants.ColumnID, constants.ColumnTitle, constants.ColumnArtist, constants.ColumnAlbum,
+	constants.ColumnGenre, constants.ColumnDuration, constants.ColumnReleaseDate,
+	constants.ColumnText, constants.ColumnLink, constants.ColumnTrackNumber,
+	constants.ColumnDiscNumber, constants.ColumnCreatedAt, constants.ColumnUpdatedAt,
+}
+
+type csvEncoder struct {
+	writer     *csv.Writer
+	withVerses bool
+	started    bool
+}
+
+func (e *csvEncoder) writeHeader() error {
+	e.started = true
+	header := csvColumns
+	if e.withVerses {
+		header = append(header[:len(header):len(header)], constants.ColumnVerses)
+	}
+	return e.writer.Write(header)
+}
+
+func (e *csvEncoder) Write(song models.SongExport) error {
+	if !e.started {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	record := []string{
+		strconv.Itoa(song.ID), song.Title, song.Artist, song.Album, song.Genre,
+		strconv.Itoa(song.Duration), song.ReleaseDate, song.Text, song.Link,
+		optionalInt(song.TrackNumber), optionalInt(song.DiscNumber),
+		song.CreatedAt.Format(time.RFC3339), song.UpdatedAt.Format(time.RFC3339),
+	}
+	if e.withVerses {
+		// куплеты не укладываются в плоскую строку, поэтому пишутся массивом JSON
+		verses, err := json.Marshal(song.Verses)
+		if err != nil {
+			return err
+		}
+		record = append(record, string(verses))
+	}
+	return e.writer.Write(record)
+}
+
+// Close записывает заголовок для пустой выгрузки и сбрасывает буфер
+func (e *csvEncoder) Close() error {
+	if !e.started {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func optionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) Write(song models.SongExport) error {
+	return e.encoder.Encode(song)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+// jsonEncoder пишет массив JSON по мере поступления песен, не накапливая их
+type jsonEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *jsonEncoder) Write(song models.SongExport) error {
+	data, err := json.Marshal(song)
+	if err != nil {
+		return err
+	}
+
+	separator := constants.JSONArraySeparator
+	if !e.started {
+		separator = constants.JSONArrayStart
+		e.started = true
+	}
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	end := constants.JSONArrayEnd
+	if !e.started {
+		end = constants.JSONArrayStart + constants.JSONArrayEnd
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+	"song-library/internal/problem"
+	"song-library/internal/repository"
+)
+
+// CatalogHandler обслуживает справочники исполнителей, альбомов и жанров
+type CatalogHandler struct {
+	artists *repository.ArtistRepository
+	albums  *repository.AlbumRepository
+	genres  *repository.GenreRepository
+	logger  *i18n.Logger
+}
+
+func NewCatalogHandler(artists *repository.ArtistRepository, albums *repository.AlbumRepository,
+	genres *repository.GenreRepository, logger *i18n.Logger) *CatalogHandler {
+	return &CatalogHandler{artists: artists, albums: albums, genres: genres, logger: logger}
+}
+
+// @Summary Получить список исполнителей
+// @Description Исполнители с количеством песен, включая участие в качестве приглашенного
+// @Tags catalog
+// @Produce json
+// @Param q query string false "Часть имени исполнителя, кириллицей или латиницей"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.ArtistsResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /artists [get]
+func (h *CatalogHandler) GetArtists(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.catalogFilter(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.artists.ListArtists(filter)
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, response)
+}
+
+// @Summary Получить исполнителя
+// @Tags catalog
+// @Produce json
+// @Param id path int true "ID исполнителя"
+// @Success 200 {object} models.Artist
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 404 {object} models.Problem "Исполнитель не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /artists/{id} [get]
+func (h *CatalogHandler) GetArtist(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	artist, err := h.artists.GetArtist(id)
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, artist)
+}
+
+// @Summary Получить песни исполнителя
+// @Description Песни, где исполнитель основной или приглашенный, по дате выпуска
+// @Tags catalog
+// @Produce json
+// @Param id path int true "ID исполнителя"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 404 {object} models.Problem "Исполнитель не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /artists/{id}/songs [get]
+func (h *CatalogHandler) GetArtistSongs(w http.ResponseWriter, r *http.Request) {
+	h.songsOf(w, r, h.artists.GetArtistSongs)
+}
+
+// @Summary Получить список альбомов
+// @Tags catalog
+// @Produce json
+// @Param q query string false "Часть названия альбома"
+// @Param artist_id query int false "ID исполнителя"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.AlbumsResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /albums [get]
+func (h *CatalogHandler) GetAlbums(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.catalogFilter(w, r)
+	if !ok {
+		return
+	}
+
+	artistID, err := queryInt(r, constants.QueryParamArtistID, 0)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+	filter.ArtistID = artistID
+
+	response, err := h.albums.ListAlbums(filter)
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, response)
+}
+
+// @Summary Получить альбом
+// @Tags catalog
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 404 {object} models.Problem "Альбом не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /albums/{id} [get]
+func (h *CatalogHandler) GetAlbum(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	album, err := h.albums.GetAlbum(id)
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, album)
+}
+
+// @Summary Изменить альбом
+// @Description Заменить год выпуска и обложку альбома. Название и исполнитель берутся из песен
+// @Tags catalog
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param album body models.AlbumUpdate true "Метаданные альбома"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Альбом не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /albums/{id} [put]
+func (h *CatalogHandler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var update models.AlbumUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return
+	}
+	if update.ReleaseYear != nil && *update.ReleaseYear < 1 {
+		problem.FromError(w, problem.Invalid(constants.FieldReleaseYear, constants.ErrInvalidReleaseYear), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.albums.UpdateAlbum(id, update); err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.logger.Printf(constants.LogSuccessAlbum, id)
+
+	album, err := h.albums.GetAlbum(id)
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, album)
+}
+
+// @Summary Получить треклист альбома
+// @Description Песни альбома по номеру диска и трека
+// @Tags catalog
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 404 {object} models.Problem "Альбом не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /albums/{id}/songs [get]
+func (h *CatalogHandler) GetAlbumSongs(w http.ResponseWriter, r *http.Request) {
+	h.songsOf(w, r, h.albums.GetAlbumSongs)
+}
+
+// @Summary Получить список жанров
+// @Tags catalog
+// @Produce json
+// @Success 200 {array} models.Genre
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /genres [get]
+func (h *CatalogHandler) GetGenres(w http.ResponseWriter, r *http.Request) {
+	genres, err := h.genres.ListGenres()
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, genres)
+}
+
+// @Summary Получить песни жанра
+// @Tags catalog
+// @Produce json
+// @Param id path int true "ID жанра"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 404 {object} models.Problem "Жанр не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /genres/{id}/songs [get]
+func (h *CatalogHandler) GetGenreSongs(w http.ResponseWriter, r *http.Request) {
+	h.songsOf(w, r, h.genres.GetGenreSongs)
+}
+
+// @Summary Получить исполнителей песни
+// @Description Основной исполнитель и приглашенные в порядке упоминания
+// @Tags catalog
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {array} models.SongArtist
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/{id}/artists [get]
+func (h *CatalogHandler) GetSongArtists(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	credits, err := h.artists.GetSongArtists(id)
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, credits)
+}
+
+// @Summary Изменить приглашенных исполнителей песни
+// @Description Заменить список приглашенных исполнителей (featuring). Новые исполнители создаются автоматически
+// @Tags catalog
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param featuring body models.SongFeaturing true "Приглашенные исполнители"
+// @Success 200 {array} models.SongArtist
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id}/artists [put]
+func (h *CatalogHandler) SetSongArtists(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.SongFeaturing
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return
+	}
+	for _, name := range input.Featuring {
+		if strings.TrimSpace(name) == "" {
+			problem.FromError(w, problem.Invalid(constants.FieldName, constants.ErrEmptyArtistName), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.artists.SetFeaturing(id, input.Featuring); err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.logger.Printf(constants.LogSuccessFeaturing, id)
+
+	credits, err := h.artists.GetSongArtists(id)
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, credits)
+}
+
+// songsOf отдает страницу песен справочника, list - метод репозитория
+func (h *CatalogHandler) songsOf(w http.ResponseWriter, r *http.Request,
+	list func(id, page, perPage int) (*models.PaginatedResponse, error)) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response, err := list(id, page, perPage)
+	if err != nil {
+		h.writeRepoError(w, err)
+		return
+	}
+	h.writeJSON(w, response)
+}
+
+func (h *CatalogHandler) catalogFilter(w http.ResponseWriter, r *http.Request) (models.CatalogFilter, bool) {
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return models.CatalogFilter{}, false
+	}
+	return models.CatalogFilter{
+		Query:   r.URL.Query().Get(constants.QueryParamQuery),
+		Page:    page,
+		PerPage: perPage,
+	}, true
+}
+
+func (h *CatalogHandler) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *CatalogHandler) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+		problem.Internal(w, constants.ErrEncodingResponse)
+	}
+}
+
+func (h *CatalogHandler) writeRepoError(w http.ResponseWriter, err error) {
+	if !problem.Known(w, err) {
+		h.logger.Printf(constants.LogError, constants.ErrGettingCatalog, err)
+		problem.Internal(w, constants.ErrGettingCatalog)
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"song-library/internal/constants"
+	"song-library/internal/models"
+	"song-library/internal/problem"
+	"song-library/internal/repository"
+)
+
+// @Summary Найти дубликаты песен
+// @Description Группы песен с одинаковыми названием и исполнителем без учета регистра, знаков препинания,
+// @Description различия ё/е и лишних пробелов. Самые многочисленные группы идут первыми
+// @Tags songs
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество групп на странице" default(10) maximum(100)
+// @Success 200 {object} models.DuplicatesResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/duplicates [get]
+func (h *SongHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.repo.ListDuplicates(page, perPage)
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrGettingDuplicates, err)
+		problem.Internal(w, constants.ErrGettingDuplicates)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
+
+// @Summary Объединить песню с дубликатом
+// @Description Переносит в песню незаполненные поля и приглашенных исполнителей дубликата и его места в плейлистах,
+// @Description куплеты дубликата добавляются после куплетов песни, текст песни пересобирается. Затем дубликат удаляется
+// @Description окончательно, без корзины, его история сохраняется. Все изменения выполняются в одной транзакции
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сохраняемой песни"
+// @Param merge body models.SongMerge true "ID удаляемого дубликата"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Новая версия песни"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня или дубликат не найдены"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id}/merge [post]
+func (h *SongHandler) MergeSong(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	var merge models.SongMerge
+	if err := json.NewDecoder(r.Body).Decode(&merge); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return
+	}
+	if merge.DuplicateID < 1 {
+		problem.FromError(w, problem.Invalid(constants.FieldDuplicateID, constants.ErrInvalidDuplicateID), http.StatusBadRequest)
+		return
+	}
+
+	song, err := h.repo.MergeSongs(r.Context(), id, merge.DuplicateID)
+	switch {
+	case errors.Is(err, repository.ErrMergeSameSong):
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	case errors.Is(err, repository.ErrSongNotFound):
+		h.logger.Printf(constants.LogSongNotFound, merge.DuplicateID)
+		problem.FromError(w, err, http.StatusNotFound)
+		return
+	case err != nil:
+		h.logger.Printf(constants.LogError, constants.ErrMergingSongs, err)
+		problem.Internal(w, constants.ErrMergingSongs)
+		return
+	}
+
+	h.logger.Printf(constants.LogSuccessMerge, id, merge.DuplicateID)
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	w.Header().Set(constants.HeaderETag, etag(song.Version))
+	if err := json.NewEncoder(w).Encode(song); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"song-library/internal/constants"
+)
+
+// etag формирует значение заголовка ETag по версии песни
+func etag(version int) string {
+	return fmt.Sprintf(constants.ETagFormat, version)
+}
+
+// parseIfMatch возвращает версии из заголовка If-Match и признак его наличия.
+// Для "*" возвращается nil - подходит любая существующая версия.
+// Слабые и нераспознанные ETag не совпадают ни с одной версией
+func parseIfMatch(r *http.Request) ([]int, bool) {
+	header := r.Header.Get(constants.HeaderIfMatch)
+	if header == "" {
+		return nil, false
+	}
+
+	versions := []int{}
+	for _, tag := range strings.Split(header, constants.ETagSeparator) {
+		tag = strings.TrimSpace(tag)
+		if tag == constants.ETagWildcard {
+			return nil, true
+		}
+		if strings.HasPrefix(tag, constants.ETagWeakPrefix) {
+			continue
+		}
+		version, err := strconv.Atoi(strings.Trim(tag, `"`))
+		if err != nil || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, true
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"song-library/internal/constants"
+	"song-library/internal/exporter"
+	"song-library/internal/models"
+	"song-library/internal/problem"
+)
+
+// @Summary Выгрузить песни
+// @Description Потоковая выгрузка всех песен, подходящих под фильтры списка песен, в порядке sort.
+// @Description Параметры page, per_page, cursor и include_total не учитываются, нечеткий поиск не поддерживается.
+// @Description CSV совместим с импортом, куплеты в нем выводятся массивом JSON в колонке verses
+// @Tags songs
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "Формат выгрузки" Enums(json, csv, ndjson) default(json)
+// @Param verses query bool false "Добавить куплеты к каждой песне" default(false)
+// @Param title query string false "Фильтр по названию"
+// @Param artist query string false "Фильтр по исполнителю"
+// @Param album query string false "Фильтр по альбому"
+// @Param genre query []string false "Фильтр по жанру, можно указать несколько раз" collectionFormat(multi)
+// @Param year query int false "Фильтр по году выпуска"
+// @Param year_from query int false "Год выпуска не раньше"
+// @Param year_to query int false "Год выпуска не позже"
+// @Param released_after query string false "Дата выпуска позже (ГГГГ-ММ-ДД)"
+// @Param released_before query string false "Дата выпуска раньше (ГГГГ-ММ-ДД)"
+// @Param duration_min query int false "Длительность не меньше, секунд"
+// @Param duration_max query int false "Длительность не больше, секунд"
+// @Param has_text query bool false "Есть текст песни"
+// @Param has_link query bool false "Есть ссылка"
+// @Param created_since query string false "Созданы не раньше (RFC3339 или ГГГГ-ММ-ДД)"
+// @Param updated_since query string false "Изменены не раньше (RFC3339 или ГГГГ-ММ-ДД)"
+// @Param sort query string false "Поля сортировки через запятую, префикс - означает убывание" default(title)
+// @Param order query string false "Направление сортировки для полей без префикса" Enums(asc, desc) default(asc)
+// @Success 200 {array} models.SongExport
+// @Header 200 {string} Content-Disposition "Имя файла выгрузки"
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/export [get]
+func (h *SongHandler) ExportSongs(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err == nil {
+		err = validateFilter(filter)
+	}
+	if err == nil && filter.Fuzzy {
+		err = errors.New(constants.ErrExportFuzzy)
+	}
+	withVerses := false
+	if err == nil {
+		withVerses, err = queryBool(r, constants.QueryParamVerses, false)
+	}
+	format := r.URL.Query().Get(constants.QueryParamFormat)
+	if format == "" {
+		format = constants.ExportFormatJSON
+	}
+
+	out := &exportWriter{ResponseWriter: w}
+	var encoder exporter.Encoder
+	if err == nil {
+		encoder, err = exporter.NewEncoder(format, out, withVerses)
+	}
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, exporter.ContentType(format))
+	w.Header().Set(constants.HeaderContentDisposition, fmt.Sprintf(constants.ContentDispositionExport,
+		time.Now().UTC().Format(constants.ExportFileDateFormat), format))
+
+	// выгрузка всей библиотеки может не уложиться в таймаут записи сервера
+	if err := extendDeadlines(w, 0, constants.ExportTimeout); err != nil {
+		h.logger.Printf(constants.LogDeadlineError, err)
+	}
+
+	err = h.repo.ExportSongs(r.Context(), filter, withVerses, func(song models.SongExport) error {
+		return encoder.Write(song)
+	})
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	// после начала ответа статус уже отправлен, остается только оборвать выгрузку
+	if out.written {
+		h.logger.Printf(constants.LogExportAborted, err)
+		return
+	}
+	h.logger.Printf(constants.LogError, constants.ErrExportFailed, err)
+	w.Header().Del(constants.HeaderContentDisposition)
+	problem.Internal(w, constants.ErrExportFailed)
+}
+
+// exportWriter отмечает, что ответ уже начал отправляться клиенту
+type exportWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *exportWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"song-library/internal/constants"
+	"song-library/internal/problem"
+	"song-library/internal/repository"
+)
+
+// @Summary Получить историю изменений песни
+// @Description Ревизии песни, новые первыми. Каждая ревизия содержит снимки полей до и после изменения,
+// @Description автора (имя API-ключа или subject токена) и ID запроса из X-Request-ID. История доступна и для песен в корзине
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество ревизий на странице" default(10) maximum(100)
+// @Success 200 {object} models.RevisionsResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/{id}/history [get]
+func (h *SongHandler) GetSongHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.repo.ListRevisions(id, page, perPage)
+	if errors.Is(err, repository.ErrSongNotFound) {
+		h.logger.Printf(constants.LogSongNotFound, id)
+		problem.FromError(w, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrGettingHistory, err)
+		problem.Internal(w, constants.ErrGettingHistory)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
+
+// @Summary Сравнить ревизии песни
+// @Description Изменившиеся поля песни между состояниями после ревизий from и to.
+// @Description Для текста песни дополнительно возвращается построчное сравнение
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param from query int true "Номер исходной ревизии"
+// @Param to query int true "Номер сравниваемой ревизии"
+// @Success 200 {object} models.RevisionDiff
+// @Failure 400 {object} models.Problem "Некорректный номер ревизии"
+// @Failure 404 {object} models.Problem "Ревизия не найдена"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/{id}/history/diff [get]
+func (h *SongHandler) GetRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	from, err := queryInt(r, constants.QueryParamFrom, 0)
+	if err != nil || from < 1 {
+		problem.FromError(w, problem.Invalid(constants.PathParamRevision, constants.ErrInvalidRevision), http.StatusBadRequest)
+		return
+	}
+	to, err := queryInt(r, constants.QueryParamTo, 0)
+	if err != nil || to < 1 {
+		problem.FromError(w, problem.Invalid(constants.PathParamRevision, constants.ErrInvalidRevision), http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.repo.DiffRevisions(id, from, to)
+	if errors.Is(err, repository.ErrRevisionNotFound) {
+		problem.FromError(w, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrGettingHistory, err)
+		problem.Internal(w, constants.ErrGettingHistory)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(diff); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
+
+// @Summary Откатить песню к ревизии
+// @Description Возвращает поля песни к состоянию после ревизии и, если текст изменился, пересобирает куплеты из восстановленного текста.
+// @Description Откат записывается в историю новой ревизией
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param rev path int true "Номер ревизии"
+// @Param If-Match header string false "ETag песни, полученный в GET"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Новая версия песни"
+// @Failure 400 {object} models.Problem "Некорректный ID или номер ревизии"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня или ревизия не найдены"
+// @Failure 409 {object} models.Problem "Песня с таким названием и исполнителем уже есть (при SONG_UNIQUE_KEY)"
+// @Failure 412 {object} models.Problem "Песня была изменена"
+// @Failure 428 {object} models.Problem "Требуется заголовок If-Match"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id}/revert/{rev} [post]
+func (h *SongHandler) RevertSong(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	revision, err := strconv.Atoi(r.PathValue(constants.PathParamRevision))
+	if err != nil || revision < 1 {
+		problem.FromError(w, problem.Invalid(constants.PathParamRevision, constants.ErrInvalidRevision), http.StatusBadRequest)
+		return
+	}
+
+	ifMatch, ok := h.ifMatch(w, r, id)
+	if !ok {
+		return
+	}
+
+	song, err := h.repo.RevertSong(r.Context(), id, revision, ifMatch)
+	if errors.Is(err, repository.ErrRevisionNotFound) {
+		h.logger.Printf(constants.LogRevisionNotFound, revision, id)
+		problem.FromError(w, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		if h.writeVersionError(w, err, id) {
+			return
+		}
+		h.logger.Printf(constants.LogError, constants.ErrRevertingSong, err)
+		problem.Internal(w, constants.ErrRevertingSong)
+		return
+	}
+
+	h.logger.Printf(constants.LogSuccessRevert, id, revision)
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	w.Header().Set(constants.HeaderETag, etag(song.Version))
+	if err := json.NewEncoder(w).Encode(song); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/importer"
+	"song-library/internal/problem"
+)
+
+type ImportHandler struct {
+	importer *importer.Importer
+	logger   *i18n.Logger
+}
+
+func NewImportHandler(importer *importer.Importer, logger *i18n.Logger) *ImportHandler {
+	return &ImportHandler{importer: importer, logger: logger}
+}
+
+// @Summary Импортировать песни
+// @Description Массовая загрузка песен из CSV (с заголовком) или NDJSON без обращения к внешнему сервису.
+// @Description Все строки загружаются в одной транзакции пакетами, отчет содержит результат каждой строки.
+// @Description Песни, уже имеющиеся в библиотеке (то же название и исполнитель), пропускаются.
+// @Description Файлы выгрузки GET /songs/export в форматах csv и ndjson загружаются без потери полей песни
+// @Tags songs
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "Формат файла, по умолчанию определяется по Content-Type" Enums(csv, ndjson)
+// @Param dry_run query bool false "Проверить файл без сохранения" default(false)
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} models.Problem "Некорректный формат или файл"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/import [post]
+func (h *ImportHandler) ImportSongs(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := queryBool(r, constants.QueryParamDryRun, false)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// чтение файла до ImportMaxBodySize и сохранение всех строк не укладываются
+	// в таймауты сервера
+	if err := extendDeadlines(w, constants.ImportTimeout, constants.ImportTimeout); err != nil {
+		h.logger.Printf(constants.LogDeadlineError, err)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, constants.ImportMaxBodySize)
+	report, err := h.importer.Import(r.Context(), r.Body, importer.Options{
+		Format: importFormat(r),
+		DryRun: dryRun,
+	})
+	if errors.Is(err, importer.ErrInvalidInput) {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrImportFailed, err)
+		problem.Internal(w, constants.ErrImportFailed)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+		problem.Internal(w, constants.ErrEncodingResponse)
+	}
+}
+
+// importFormat берет формат из параметра format, иначе из Content-Type
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get(constants.QueryParamFormat); format != "" {
+		return format
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(constants.HeaderContentType))
+	switch mediaType {
+	case constants.ContentTypeCSV:
+		return constants.ImportFormatCSV
+	case constants.ContentTypeNDJSON:
+		return constants.ImportFormatNDJSON
+	}
+	return mediaType
+}
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+	"song-library/internal/problem"
+	"song-library/internal/repository"
+)
+
+// PlaylistHandler обслуживает плейлисты и их элементы
+type PlaylistHandler struct {
+	repo   *repository.PlaylistRepository
+	logger *i18n.Logger
+}
+
+func NewPlaylistHandler(repo *repository.PlaylistRepository, logger *i18n.Logger) *PlaylistHandler {
+	return &PlaylistHandler{repo: repo, logger: logger}
+}
+
+// @Summary Получить список плейлистов
+// @Description Плейлисты по названию с количеством песен и общей длительностью
+// @Tags playlists
+// @Produce json
+// @Param q query string false "Часть названия плейлиста"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.PlaylistsResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /playlists [get]
+func (h *PlaylistHandler) GetPlaylists(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.repo.ListPlaylists(models.CatalogFilter{
+		Query:   r.URL.Query().Get(constants.QueryParamQuery),
+		Page:    page,
+		PerPage: perPage,
+	})
+	if err != nil {
+		h.writeRepoError(w, err, constants.ErrGettingPlaylists)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary Создать плейлист
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param playlist body models.PlaylistInput true "Название и описание"
+// @Success 201 {object} models.Playlist
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /playlists [post]
+func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
+	input, ok := h.decodePlaylistInput(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := h.repo.CreatePlaylist(input)
+	if err != nil {
+		h.writeRepoError(w, err, constants.ErrSavingPlaylist)
+		return
+	}
+	h.logger.Printf(constants.LogSuccessPlaylist, id)
+
+	playlist, err := h.repo.GetPlaylist(id)
+	if err != nil {
+		h.writeRepoError(w, err, constants.ErrGettingPlaylists)
+		return
+	}
+	h.writeJSON(w, http.StatusCreated, playlist)
+}
+
+// @Summary Получить плейлист
+// @Tags playlists
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 404 {object} models.Problem "Плейлист не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [get]
+func (h *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	playlist, err := h.repo.GetPlaylist(id)
+	if err != nil {
+		h.writeRepoError(w, err, constants.ErrGettingPlaylists)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, playlist)
+}
+
+// @Summary Изменить плейлист
+// @Description Заменить название и описание плейлиста
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param playlist body models.PlaylistInput true "Название и описание"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Плейлист не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /playlists/{id} [put]
+func (h *PlaylistHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	input, ok := h.decodePlaylistInput(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.UpdatePlaylist(id, input); err != nil {
+		h.writeRepoError(w, err, constants.ErrSavingPlaylist)
+		return
+	}
+	h.logger.Printf(constants.LogSuccessPlaylist, id)
+
+	playlist, err := h.repo.GetPlaylist(id)
+	if err != nil {
+		h.writeRepoError(w, err, constants.ErrGettingPlaylists)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, playlist)
+}
+
+// @Summary Удалить плейлист
+// @Description Удалить плейлист вместе с элементами, песни остаются в библиотеке
+// @Tags playlists
+// @Param id path int true "ID плейлиста"
+// @Success 204 "Плейлист удален"
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Плейлист не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /playlists/{id} [delete]
+func (h *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeletePlaylist(id); err != nil {
+		h.writeRepoError(w, err, constants.ErrDeletingPlaylist)
+		return
+	}
+	h.logger.Printf(constants.LogDeletedPlaylist, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Получить элементы плейлиста
+// @Description Песни плейлиста по позициям. Песни из корзины сохраняют место и помечаются deleted,
+// @Description окончательно удаленные песни исчезают из плейлиста, а последующие элементы сдвигаются
+// @Tags playlists
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.PlaylistItemsResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 404 {object} models.Problem "Плейлист не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/items [get]
+func (h *PlaylistHandler) GetItems(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.repo.ListItems(id, page, perPage)
+	if err != nil {
+		h.writeRepoError(w, err, constants.ErrGettingPlaylists)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary Добавить песню в плейлист
+// @Description Вставить песню на позицию, последующие элементы сдвигаются. Без позиции песня добавляется в конец
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param item body models.PlaylistItemInput true "Песня и позиция"
+// @Success 201 {object} models.PlaylistItem
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Плейлист или песня не найдены"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /playlists/{id}/items [post]
+func (h *PlaylistHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.PlaylistItemInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return
+	}
+	if input.SongID < 1 {
+		problem.FromError(w, problem.Invalid(constants.FieldSongID, constants.ErrInvalidSongID), http.StatusBadRequest)
+		return
+	}
+	if input.Position < 0 {
+		problem.FromError(w, problem.Invalid(constants.FieldPosition, constants.ErrInvalidPosition), http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.repo.AddItem(id, input)
+	if err != nil {
+		h.writeRepoError(w, err, constants.ErrChangingPlaylist)
+		return
+	}
+	h.logger.Printf(constants.LogPlaylistItemAdded, id, item.SongID, item.Position)
+	h.writeJSON(w, http.StatusCreated, item)
+}
+
+// @Summary Изменить порядок элементов плейлиста
+// @Description Задать новый порядок всех элементов: item_ids должен содержать каждый элемент ровно один раз
+// @Tags playlists
+// @Accept json
+// @Param id path int true "ID плейлиста"
+// @Param order body models.PlaylistReorder true "ID элементов в новом порядке"
+// @Success 200 "Порядок изменен"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Плейлист не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /playlists/{id}/items [put]
+func (h *PlaylistHandler) ReorderItems(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.PlaylistReorder
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.ReorderItems(id, input.ItemIDs); err != nil {
+		h.writeRepoError(w, err, constants.ErrChangingPlaylist)
+		return
+	}
+	h.logger.Printf(constants.LogPlaylistReordered, id)
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Переместить элемент плейлиста
+// @Description Перенести элемент на новую позицию, нумерация остается непрерывной.
+// @Description Позиция за последней заменяется последней
+// @Tags playlists
+// @Accept json
+// @Param id path int true "ID плейлиста"
+// @Param item path int true "ID элемента"
+// @Param move body models.PlaylistItemMove true "Новая позиция"
+// @Success 200 "Элемент перемещен"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Плейлист или элемент не найдены"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /playlists/{id}/items/{item}/position [put]
+func (h *PlaylistHandler) MoveItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, ok := h.itemID(w, r)
+	if !ok {
+		return
+	}
+
+	var move models.PlaylistItemMove
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return
+	}
+	if move.Position < 1 {
+		problem.FromError(w, problem.Invalid(constants.FieldPosition, constants.ErrInvalidPosition), http.StatusBadRequest)
+		return
+	}
+
+	position, err := h.repo.MoveItem(id, itemID, move.Position)
+	if err != nil {
+		h.writeRepoError(w, err, constants.ErrChangingPlaylist)
+		return
+	}
+	h.logger.Printf(constants.LogPlaylistItemMoved, itemID, id, position)
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Удалить элемент плейлиста
+// @Description Убрать песню из плейлиста, последующие элементы сдвигаются
+// @Tags playlists
+// @Param id path int true "ID плейлиста"
+// @Param item path int true "ID элемента"
+// @Success 204 "Элемент удален"
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Плейлист или элемент не найдены"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /playlists/{id}/items/{item} [delete]
+func (h *PlaylistHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, ok := h.itemID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.RemoveItem(id, itemID); err != nil {
+		h.writeRepoError(w, err, constants.ErrChangingPlaylist)
+		return
+	}
+	h.logger.Printf(constants.LogPlaylistItemRemove, id, itemID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PlaylistHandler) decodePlaylistInput(w http.ResponseWriter, r *http.Request) (models.PlaylistInput, bool) {
+	var input models.PlaylistInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return input, false
+	}
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		problem.FromError(w, problem.Invalid(constants.FieldName, constants.ErrPlaylistNameRequired), http.StatusBadRequest)
+		return input, false
+	}
+	return input, true
+}
+
+func (h *PlaylistHandler) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// itemID читает ID плейлиста и ID его элемента из пути
+func (h *PlaylistHandler) itemID(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	itemID, err := strconv.Atoi(r.PathValue(constants.PathParamItem))
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, itemID, true
+}
+
+func (h *PlaylistHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
+
+// writeRepoError сопоставляет ошибки репозитория со статусами ответа
+func (h *PlaylistHandler) writeRepoError(w http.ResponseWriter, err error, fallback string) {
+	if !problem.Known(w, err) {
+		h.logger.Printf(constants.LogError, fallback, err)
+		problem.Internal(w, fallback)
+	}
+}
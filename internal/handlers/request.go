@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"song-library/internal/constants"
+	"song-library/internal/problem"
+)
+
+// resourceID читает ID ресурса из параметра пути {id}, а для устаревших
+// маршрутов - из параметра запроса queryParam
+func resourceID(r *http.Request, queryParam string) (int, error) {
+	value := r.PathValue(constants.PathParamID)
+	if value == "" {
+		value = r.URL.Query().Get(queryParam)
+	}
+	return strconv.Atoi(value)
+}
+
+// extendDeadlines продлевает сроки чтения запроса и записи ответа сверх таймаутов
+// сервера для долгих операций, нулевой timeout оставляет срок без изменений
+func extendDeadlines(w http.ResponseWriter, readTimeout, writeTimeout time.Duration) error {
+	rc := http.NewResponseController(w)
+	if readTimeout > 0 {
+		if err := rc.SetReadDeadline(time.Now().Add(readTimeout)); err != nil {
+			return err
+		}
+	}
+	if writeTimeout > 0 {
+		return rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	}
+	return nil
+}
+
+// queryInt читает целочисленный параметр запроса, defaultValue - если параметр не передан
+func queryInt(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, problem.Invalid(name, constants.ErrInvalidQueryParam, name)
+	}
+	return parsed, nil
+}
+
+// queryBool читает логический параметр запроса, defaultValue - если параметр не передан
+func queryBool(r *http.Request, name string, defaultValue bool) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, problem.Invalid(name, constants.ErrInvalidQueryParam, name)
+	}
+	return parsed, nil
+}
+
+// queryOptionalInt читает необязательный целочисленный параметр, nil - если параметр не передан
+func queryOptionalInt(r *http.Request, name string) (*int, error) {
+	if r.URL.Query().Get(name) == "" {
+		return nil, nil
+	}
+	parsed, err := queryInt(r, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// queryOptionalBool читает необязательный логический параметр, nil - если параметр не передан
+func queryOptionalBool(r *http.Request, name string) (*bool, error) {
+	if r.URL.Query().Get(name) == "" {
+		return nil, nil
+	}
+	parsed, err := queryBool(r, name, false)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// queryDate читает дату в формате ГГГГ-ММ-ДД, пустая строка - если параметр не передан
+func queryDate(r *http.Request, name string) (string, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return "", nil
+	}
+	if _, err := time.Parse(constants.DateFormat, value); err != nil {
+		return "", problem.Invalid(name, constants.ErrInvalidQueryParam, name)
+	}
+	return value, nil
+}
+
+// queryTime читает момент времени в формате RFC 3339 или дату ГГГГ-ММ-ДД,
+// nil - если параметр не передан
+func queryTime(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if parsed, err = time.Parse(constants.DateFormat, value); err != nil {
+			return nil, problem.Invalid(name, constants.ErrInvalidQueryParam, name)
+		}
+	}
+	return &parsed, nil
+}
+
+// requireFields принимает пары имя поля - значение и возвращает ошибку валидации
+// для каждого пустого поля
+func requireFields(fieldValues ...string) error {
+	var invalid problem.ValidationError
+	for i := 0; i+1 < len(fieldValues); i += 2 {
+		if fieldValues[i+1] == "" {
+			invalid.Add(fieldValues[i], constants.ErrRequiredFields)
+		}
+	}
+	return invalid.Err()
+}
@@ -1,31 +1,37 @@
 package handlers
 
 import (
-	"database/sql"
 	"encoding/json"
-	"fmt"
+	"mime"
 	"net/http"
-	"net/url"
-	"strconv"
+	"strings"
+	"time"
 
 	"errors"
-	"log"
 	"song-library/internal/constants"
+	"song-library/internal/i18n"
 	"song-library/internal/models"
+	"song-library/internal/problem"
 	"song-library/internal/repository"
+	"song-library/internal/songinfo"
 )
 
 type SongHandler struct {
-	repo          *repository.SongRepository
-	logger        *log.Logger
-	ServerAddress string
+	repo           *repository.SongRepository
+	logger         *i18n.Logger
+	songInfo       songinfo.SongInfoProvider
+	requireIfMatch bool
 }
 
-func NewSongHandler(repo *repository.SongRepository, logger *log.Logger, ServerAddress string) *SongHandler {
+// NewSongHandler создает обработчик песен, songInfo может быть nil - тогда песни
+// создаются без обогащения данными внешнего сервиса. При requireIfMatch изменение
+// и удаление песни без заголовка If-Match отклоняется с 428
+func NewSongHandler(repo *repository.SongRepository, logger *i18n.Logger, songInfo songinfo.SongInfoProvider, requireIfMatch bool) *SongHandler {
 	return &SongHandler{
-		repo:          repo,
-		logger:        logger,
-		ServerAddress: ServerAddress,
+		repo:           repo,
+		logger:         logger,
+		songInfo:       songInfo,
+		requireIfMatch: requireIfMatch,
 	}
 }
 
@@ -37,32 +43,50 @@ func NewSongHandler(repo *repository.SongRepository, logger *log.Logger, ServerA
 // @Param title query string false "Название песни"
 // @Param artist query string false "Исполнитель"
 // @Param album query string false "Альбом"
-// @Param genre query string false "Жанр"
+// @Param genre query []string false "Жанр, можно передать несколько значений" collectionFormat(multi)
 // @Param year query int false "Год выпуска"
+// @Param year_from query int false "Год выпуска не раньше"
+// @Param year_to query int false "Год выпуска не позже"
+// @Param released_after query string false "Выпущена после даты (ГГГГ-ММ-ДД)"
+// @Param released_before query string false "Выпущена до даты (ГГГГ-ММ-ДД)"
+// @Param duration_min query int false "Минимальная длительность, секунды"
+// @Param duration_max query int false "Максимальная длительность, секунды"
+// @Param has_text query bool false "Есть текст песни"
+// @Param has_link query bool false "Есть ссылка"
+// @Param created_since query string false "Созданы не раньше момента (RFC 3339 или ГГГГ-ММ-ДД)"
+// @Param updated_since query string false "Изменены не раньше момента (RFC 3339 или ГГГГ-ММ-ДД)"
 // @Param page query int false "Номер страницы" default(1)
 // @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Param sort query string false "Поля сортировки через запятую, префикс - означает убывание: title, artist, album, release_date, duration, created_at, updated_at" default(title)
+// @Param order query string false "Направление сортировки для полей без префикса" Enums(asc, desc) default(asc)
+// @Param cursor query string false "Курсор страницы из next_cursor, пустое значение - первая страница. Заменяет page"
+// @Param include_total query bool false "Возвращать total и total_pages" default(true)
+// @Param fuzzy query bool false "Поиск с опечатками, результаты упорядочены по сходству" default(false)
+// @Param translit query bool false "Искать также транслитерацию запроса (кириллица/латиница)" default(false)
 // @Success 200 {object} models.PaginatedResponse
-// @Failure 400 {string} string "Ошибка валидации"
-// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
 // @Router /songs [get]
 func (h *SongHandler) GetSongs(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.logger.Printf(constants.LogMethodNotAllowed, r.Method, constants.HandlerGetSongs)
-		http.Error(w, constants.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
-		return
+	filter, err := parseFilter(r)
+	if err == nil {
+		err = validateFilter(filter)
 	}
-
-	filter := parseFilter(r)
-	if err := validateFilter(filter); err != nil {
+	if err != nil {
 		h.logger.Printf(constants.LogValidationError, err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.FromError(w, err, http.StatusBadRequest)
 		return
 	}
 
 	response, err := h.repo.ListSongs(filter)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		h.logger.Printf(constants.LogError, constants.ErrGettingSongs, err)
-		http.Error(w, constants.ErrGettingSongs, http.StatusInternalServerError)
+		problem.Internal(w, constants.ErrGettingSongs)
 		return
 	}
 
@@ -70,77 +94,274 @@ func (h *SongHandler) GetSongs(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.Printf(constants.LogEncodingError, err)
-		http.Error(w, constants.ErrEncodingResponse, http.StatusInternalServerError)
+		problem.Internal(w, constants.ErrEncodingResponse)
+		return
+	}
+}
+
+// @Summary Получить песню
+// @Description Получить песню по ID со всеми полями, включая даты создания и изменения
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Версия песни для If-Match"
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/{id} [get]
+func (h *SongHandler) GetSong(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	song, err := h.repo.GetSong(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSongNotFound) {
+			h.logger.Printf(constants.LogSongNotFound, id)
+			problem.FromError(w, err, http.StatusNotFound)
+			return
+		}
+		h.logger.Printf(constants.LogError, constants.ErrGettingSong, err)
+		problem.Internal(w, constants.ErrGettingSong)
+		return
+	}
+
+	w.Header().Set(constants.HeaderCacheControl, constants.CacheControlValue)
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	w.Header().Set(constants.HeaderETag, etag(song.Version))
+	if err := json.NewEncoder(w).Encode(song); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+		problem.Internal(w, constants.ErrEncodingResponse)
 		return
 	}
 }
 
-func parseFilter(r *http.Request) models.SongFilter {
+// parseFilter читает параметры списка песен, некорректные значения возвращаются ошибкой
+func parseFilter(r *http.Request) (models.SongFilter, error) {
+	query := r.URL.Query()
 	filter := models.SongFilter{
-		Title:   r.URL.Query().Get(constants.QueryParamTitle),
-		Artist:  r.URL.Query().Get(constants.QueryParamArtist),
-		Album:   r.URL.Query().Get(constants.QueryParamAlbum),
-		Genre:   r.URL.Query().Get(constants.QueryParamGenre),
-		Page:    constants.DefaultPage,
-		PerPage: constants.DefaultPageSize,
+		Title:     query.Get(constants.QueryParamTitle),
+		Artist:    query.Get(constants.QueryParamArtist),
+		Album:     query.Get(constants.QueryParamAlbum),
+		Order:     strings.ToLower(query.Get(constants.QueryParamOrder)),
+		UseCursor: query.Has(constants.QueryParamCursor),
+		Cursor:    query.Get(constants.QueryParamCursor),
+	}
+	for _, genre := range query[constants.QueryParamGenre] {
+		if genre != "" {
+			filter.Genres = append(filter.Genres, genre)
+		}
 	}
 
-	if year := r.URL.Query().Get(constants.QueryParamYear); year != "" {
-		filter.Year, _ = strconv.Atoi(year)
+	var err error
+	if filter.Year, err = queryInt(r, constants.QueryParamYear, 0); err != nil {
+		return filter, err
+	}
+	if filter.Page, err = queryInt(r, constants.QueryParamPage, constants.DefaultPage); err != nil {
+		return filter, err
+	}
+	if filter.PerPage, err = queryInt(r, constants.QueryParamPerPage, constants.DefaultPageSize); err != nil {
+		return filter, err
+	}
+	if filter.YearFrom, err = queryOptionalInt(r, constants.QueryParamYearFrom); err != nil {
+		return filter, err
+	}
+	if filter.YearTo, err = queryOptionalInt(r, constants.QueryParamYearTo); err != nil {
+		return filter, err
+	}
+	if filter.ReleasedAfter, err = queryDate(r, constants.QueryParamReleasedAfter); err != nil {
+		return filter, err
+	}
+	if filter.ReleasedBefore, err = queryDate(r, constants.QueryParamReleasedBefore); err != nil {
+		return filter, err
+	}
+	if filter.DurationMin, err = queryOptionalInt(r, constants.QueryParamDurationMin); err != nil {
+		return filter, err
+	}
+	if filter.DurationMax, err = queryOptionalInt(r, constants.QueryParamDurationMax); err != nil {
+		return filter, err
+	}
+	if filter.HasText, err = queryOptionalBool(r, constants.QueryParamHasText); err != nil {
+		return filter, err
+	}
+	if filter.HasLink, err = queryOptionalBool(r, constants.QueryParamHasLink); err != nil {
+		return filter, err
+	}
+	if filter.CreatedSince, err = queryTime(r, constants.QueryParamCreatedSince); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedSince, err = queryTime(r, constants.QueryParamUpdatedSince); err != nil {
+		return filter, err
 	}
-	if page := r.URL.Query().Get(constants.QueryParamPage); page != "" {
-		filter.Page, _ = strconv.Atoi(page)
+	if filter.IncludeTotal, err = queryBool(r, constants.QueryParamTotal, true); err != nil {
+		return filter, err
 	}
-	if perPage := r.URL.Query().Get(constants.QueryParamPerPage); perPage != "" {
-		filter.PerPage, _ = strconv.Atoi(perPage)
+	if filter.Fuzzy, err = queryBool(r, constants.QueryParamFuzzy, false); err != nil {
+		return filter, err
+	}
+	if filter.Translit, err = queryBool(r, constants.QueryParamTranslit, false); err != nil {
+		return filter, err
+	}
+
+	if sort := query.Get(constants.QueryParamSort); sort != "" {
+		for _, name := range strings.Split(sort, constants.SortSeparator) {
+			name = strings.TrimSpace(name)
+			field := models.SortField{Name: name, Desc: filter.Order == constants.SortOrderDesc}
+			if trimmed, ok := strings.CutPrefix(name, constants.SortDescPrefix); ok {
+				field = models.SortField{Name: trimmed, Desc: true}
+			}
+			filter.Sort = append(filter.Sort, field)
+		}
+	} else if filter.Order == constants.SortOrderDesc {
+		filter.Sort = []models.SortField{{Name: constants.DefaultSongSort, Desc: true}}
 	}
 
-	return filter
+	return filter, nil
 }
 
 func validateFilter(f models.SongFilter) error {
-	if f.Page < 1 {
-		return errors.New(constants.ErrInvalidPage)
+	var invalid problem.ValidationError
+	if f.YearFrom != nil && f.YearTo != nil && *f.YearFrom > *f.YearTo {
+		invalid.Add(constants.QueryParamYearFrom, constants.ErrInvalidRange, constants.QueryParamYearFrom, constants.QueryParamYearTo)
+	}
+	if f.DurationMin != nil && *f.DurationMin < 0 {
+		invalid.Add(constants.QueryParamDurationMin, constants.ErrInvalidQueryParam, constants.QueryParamDurationMin)
+	}
+	if f.DurationMin != nil && f.DurationMax != nil && *f.DurationMin > *f.DurationMax {
+		invalid.Add(constants.QueryParamDurationMin, constants.ErrInvalidRange, constants.QueryParamDurationMin, constants.QueryParamDurationMax)
+	}
+	if f.ReleasedAfter != "" && f.ReleasedBefore != "" && f.ReleasedAfter > f.ReleasedBefore {
+		invalid.Add(constants.QueryParamReleasedAfter, constants.ErrInvalidRange, constants.QueryParamReleasedAfter, constants.QueryParamReleasedBefore)
+	}
+	if f.UseCursor && f.Fuzzy {
+		invalid.Add(constants.QueryParamCursor, constants.ErrCursorWithFuzzy)
+	}
+	if f.Order != "" && f.Order != constants.SortOrderAsc && f.Order != constants.SortOrderDesc {
+		invalid.Add(constants.QueryParamOrder, constants.ErrInvalidSortOrder)
+	}
+	for _, field := range f.Sort {
+		if !repository.IsSongSortField(field.Name) {
+			invalid.Add(constants.QueryParamSort, constants.ErrInvalidSortField, field.Name)
+		}
+	}
+	checkPagination(&invalid, f.Page, f.PerPage)
+	return invalid.Err()
+}
+
+func validatePagination(page, perPage int) error {
+	var invalid problem.ValidationError
+	checkPagination(&invalid, page, perPage)
+	return invalid.Err()
+}
+
+// checkPagination добавляет в invalid ошибки параметров page и per_page
+func checkPagination(invalid *problem.ValidationError, page, perPage int) {
+	if page < 1 {
+		invalid.Add(constants.QueryParamPage, constants.ErrInvalidPage)
+	}
+	if perPage < 1 || perPage > 100 {
+		invalid.Add(constants.QueryParamPerPage, constants.ErrInvalidPerPage)
+	}
+}
+
+// @Summary Поиск по текстам песен
+// @Description Полнотекстовый поиск по текстам песен и куплетам (русская и английская морфология).
+// @Description Поддерживается синтаксис websearch: "точная фраза", OR, -исключение.
+// @Description Результаты упорядочены по релевантности, совпадения в snippet выделены тегом mark
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Поисковый запрос"
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.SearchResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /search [get]
+func (h *SongHandler) SearchSongs(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get(constants.QueryParamQuery))
+	if query == "" {
+		h.logger.Print(constants.LogMissingFields)
+		problem.FromError(w, problem.Invalid(constants.QueryParamQuery, constants.ErrSearchQueryRequired), http.StatusBadRequest)
+		return
+	}
+
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.repo.SearchSongs(query, page, perPage)
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrSearchingSongs, err)
+		problem.Internal(w, constants.ErrSearchingSongs)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+		problem.Internal(w, constants.ErrEncodingResponse)
+		return
+	}
+}
+
+// parsePagination читает и проверяет параметры page и per_page
+func parsePagination(r *http.Request) (int, int, error) {
+	page, err := queryInt(r, constants.QueryParamPage, constants.DefaultPage)
+	if err != nil {
+		return 0, 0, err
 	}
-	if f.PerPage < 1 || f.PerPage > 100 {
-		return errors.New(constants.ErrInvalidPerPage)
+	perPage, err := queryInt(r, constants.QueryParamPerPage, constants.DefaultPageSize)
+	if err != nil {
+		return 0, 0, err
 	}
-	return nil
+	return page, perPage, validatePagination(page, perPage)
 }
 
 // @Summary Удалить песню
-// @Description Удалить песню по ID
+// @Description Переместить песню в корзину. Ее можно восстановить, пока не истек срок хранения TRASH_RETENTION
 // @Tags songs
 // @Accept json
 // @Produce json
-// @Param id query int true "ID песни"
+// @Param id path int true "ID песни"
+// @Param If-Match header string false "ETag песни, полученный в GET"
 // @Success 204 "Песня успешно удалена"
-// @Failure 400 {string} string "Некорректный ID"
-// @Failure 404 {string} string "Песня не найдена"
-// @Failure 500 {string} string "Внутренняя ошибка сервера"
-// @Router /songs/delete [delete]
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 412 {object} models.Problem "Песня была изменена"
+// @Failure 428 {object} models.Problem "Требуется заголовок If-Match"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id} [delete]
 func (h *SongHandler) DeleteSong(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		h.logger.Printf(constants.LogMethodNotAllowed, r.Method, constants.HandlerDeleteSong)
-		http.Error(w, constants.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
 		return
 	}
 
-	id, err := strconv.Atoi(r.URL.Query().Get(constants.QueryParamID))
-	if err != nil {
-		h.logger.Printf(constants.LogInvalidID, err)
-		http.Error(w, constants.ErrInvalidID, http.StatusBadRequest)
+	ifMatch, ok := h.ifMatch(w, r, id)
+	if !ok {
 		return
 	}
 
-	if err := h.repo.DeleteSong(id); err != nil {
-		if err == sql.ErrNoRows {
-			h.logger.Printf(constants.LogSongNotFound, id)
-			http.Error(w, constants.ErrSongNotFound, http.StatusNotFound)
+	if err := h.repo.DeleteSong(r.Context(), id, ifMatch); err != nil {
+		if h.writeVersionError(w, err, id) {
 			return
 		}
 		h.logger.Printf(constants.LogError, constants.ErrDeletingSong, err)
-		http.Error(w, constants.ErrDeletingSong, http.StatusInternalServerError)
+		problem.Internal(w, constants.ErrDeletingSong)
 		return
 	}
 
@@ -149,28 +370,31 @@ func (h *SongHandler) DeleteSong(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Обновить песню
-// @Description Обновить информацию о песне
+// @Description Обновить информацию о песне. Измененный текст разбивается по пустым строкам на куплеты, заменяющие существующие.
+// @Description Неизмененный текст куплеты не трогает, пустой текст удаляет все куплеты
 // @Tags songs
 // @Accept json
 // @Produce json
-// @Param id query int true "ID песни"
+// @Param id path int true "ID песни"
 // @Param song body models.SongUpdate true "Данные песни"
+// @Param If-Match header string false "ETag песни, полученный в GET"
 // @Success 200 "Песня успешно обновлена"
-// @Failure 400 {string} string "Некорректные данные"
-// @Failure 404 {string} string "Песня не найдена"
-// @Failure 500 {string} string "Внутренняя ошибка сервера"
-// @Router /songs/update [put]
+// @Header 200 {string} ETag "Новая версия песни"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 409 {object} models.Problem "Песня с таким названием и исполнителем уже есть (при SONG_UNIQUE_KEY)"
+// @Failure 412 {object} models.Problem "Песня была изменена"
+// @Failure 428 {object} models.Problem "Требуется заголовок If-Match"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id} [put]
 func (h *SongHandler) UpdateSong(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		h.logger.Printf(constants.LogMethodNotAllowed, r.Method, constants.HandlerUpdateSong)
-		http.Error(w, constants.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
-		return
-	}
-
-	id, err := strconv.Atoi(r.URL.Query().Get(constants.QueryParamID))
+	id, err := resourceID(r, constants.QueryParamID)
 	if err != nil {
 		h.logger.Printf(constants.LogInvalidID, err)
-		http.Error(w, constants.ErrInvalidID, http.StatusBadRequest)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
 		return
 	}
 
@@ -178,7 +402,7 @@ func (h *SongHandler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 	contentType := r.Header.Get(constants.HeaderContentType)
 	if contentType != constants.HeaderContentTypeJSON {
 		h.logger.Printf(constants.LogInvalidContentType, contentType)
-		http.Error(w, constants.ErrInvalidContentType, http.StatusBadRequest)
+		problem.Error(w, constants.CodeInvalidContentType, constants.ErrInvalidContentType, http.StatusBadRequest)
 		return
 	}
 
@@ -188,89 +412,226 @@ func (h *SongHandler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 	var songUpdate models.SongUpdate
 	if err := json.NewDecoder(r.Body).Decode(&songUpdate); err != nil {
 		h.logger.Printf(constants.LogDecodingError, err)
-		http.Error(w, constants.ErrInvalidData, http.StatusBadRequest)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
 		return
 	}
 
-	if songUpdate.Title == "" || songUpdate.Artist == "" {
+	if err := requireFields(constants.FieldTitle, songUpdate.Title, constants.FieldArtist, songUpdate.Artist); err != nil {
 		h.logger.Print(constants.LogMissingFields)
-		http.Error(w, constants.ErrRequiredFields, http.StatusBadRequest)
+		problem.FromError(w, err, http.StatusBadRequest)
 		return
 	}
 
-	if err := h.repo.UpdateSong(id, songUpdate); err != nil {
-		h.logger.Printf(constants.LogError, constants.ErrUpdatingSong, err)
-		if err.Error() == constants.ErrSongNotFound {
-			http.Error(w, constants.ErrSongNotFound, http.StatusNotFound)
+	ifMatch, ok := h.ifMatch(w, r, id)
+	if !ok {
+		return
+	}
+
+	version, err := h.repo.UpdateSong(r.Context(), id, songUpdate, ifMatch)
+	if err != nil {
+		if h.writeVersionError(w, err, id) {
 			return
 		}
-		http.Error(w, constants.ErrUpdatingSong, http.StatusInternalServerError)
+		h.logger.Printf(constants.LogError, constants.ErrUpdatingSong, err)
+		problem.Internal(w, constants.ErrUpdatingSong)
 		return
 	}
 
 	h.logger.Printf(constants.LogSuccessUpdate, id)
+	w.Header().Set(constants.HeaderETag, etag(version))
 	w.WriteHeader(http.StatusOK)
 }
 
-// @Summary Создать песню
-// @Description Создать новую песню
+// @Summary Частично обновить песню
+// @Description Обновить только переданные поля песни (JSON Merge Patch, RFC 7396): отсутствующие поля не меняются, null очищает значение.
+// @Description Измененный текст разбивается на куплеты, заменяющие существующие, пустой или null текст удаляет куплеты
 // @Tags songs
 // @Accept json
+// @Accept application/merge-patch+json
 // @Produce json
-// @Param input body models.SimpleSongInput true "Данные песни"
-// @Success 201 {object} map[string]int "ID созданной песни"
-// @Failure 400 {string} string "Некорректные данные"
-// @Failure 500 {string} string "Внутренняя ошибка сервера"
-// @Router /songs/create [post]
-func (h *SongHandler) CreateSong(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.logger.Printf(constants.LogMethodNotAllowed, r.Method, constants.HandlerCreateSong)
-		http.Error(w, constants.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
+// @Param id path int true "ID песни"
+// @Param song body models.SongPatch true "Изменяемые поля песни"
+// @Param If-Match header string false "ETag песни, полученный в GET"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Новая версия песни"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 409 {object} models.Problem "Песня с таким названием и исполнителем уже есть (при SONG_UNIQUE_KEY)"
+// @Failure 412 {object} models.Problem "Песня была изменена"
+// @Failure 428 {object} models.Problem "Требуется заголовок If-Match"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id} [patch]
+func (h *SongHandler) PatchSong(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
 		return
 	}
 
-	// Декодируем входящий JSON
-	var input struct {
-		Group string `json:"group"`
-		Song  string `json:"song"`
+	contentType := r.Header.Get(constants.HeaderContentType)
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != constants.ContentTypeMergePatch &&
+		mediaType != constants.HeaderContentTypeJSON {
+		h.logger.Printf(constants.LogInvalidContentType, contentType)
+		problem.Error(w, constants.CodeUnsupportedPatchType, constants.ErrInvalidPatchType, http.StatusUnsupportedMediaType)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576) // 1MB limit
+
+	var patch models.SongPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
 		h.logger.Printf(constants.LogDecodingError, err)
-		http.Error(w, constants.ErrDecodingJSON, http.StatusBadRequest)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
 		return
 	}
 
-	// Запрос к API для получения дополнительной информации
-	apiURL := fmt.Sprintf(constants.DefaultFormat,
-		h.ServerAddress,
-		fmt.Sprintf(constants.APIInfoURLFormat,
-			url.QueryEscape(input.Group),
-			url.QueryEscape(input.Song)))
+	if err := validatePatch(patch); err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
 
-	resp, err := http.Get(apiURL)
+	ifMatch, ok := h.ifMatch(w, r, id)
+	if !ok {
+		return
+	}
+
+	song, err := h.repo.PatchSong(r.Context(), id, patch, ifMatch)
 	if err != nil {
-		h.logger.Printf(constants.LogError, constants.ErrFetchingSongInfo, err)
-		http.Error(w, constants.ErrFetchingSongInfo, http.StatusInternalServerError)
+		if h.writeVersionError(w, err, id) {
+			return
+		}
+		h.logger.Printf(constants.LogError, constants.ErrUpdatingSong, err)
+		problem.Internal(w, constants.ErrUpdatingSong)
 		return
 	}
-	defer resp.Body.Close()
 
-	var song models.Song
-	if err := json.NewDecoder(resp.Body).Decode(&song); err != nil {
-		h.logger.Printf(constants.LogError, constants.ErrProcessingSongInfo, err)
-		http.Error(w, constants.ErrProcessingSongInfo, http.StatusInternalServerError)
+	h.logger.Printf(constants.LogSuccessPatch, id)
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	w.Header().Set(constants.HeaderETag, etag(song.Version))
+	if err := json.NewEncoder(w).Encode(song); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
+
+// ifMatch читает заголовок If-Match и отвечает 428, если он обязателен, но не передан
+func (h *SongHandler) ifMatch(w http.ResponseWriter, r *http.Request, id int) ([]int, bool) {
+	versions, present := parseIfMatch(r)
+	if !present && h.requireIfMatch {
+		h.logger.Printf(constants.LogIfMatchMissing, id)
+		problem.Error(w, constants.CodePreconditionRequired, constants.ErrPreconditionRequired, http.StatusPreconditionRequired)
+		return nil, false
+	}
+	return versions, true
+}
+
+// writeVersionError отвечает 404, 409 или 412 для соответствующих ошибок репозитория
+func (h *SongHandler) writeVersionError(w http.ResponseWriter, err error, id int) bool {
+	switch {
+	case errors.Is(err, repository.ErrSongNotFound):
+		h.logger.Printf(constants.LogSongNotFound, id)
+	case errors.Is(err, repository.ErrDuplicateSong):
+		h.logger.Printf(constants.LogError, constants.ErrUpdatingSong, err)
+	case errors.Is(err, repository.ErrVersionMismatch):
+		h.logger.Printf(constants.LogVersionMismatch, id)
+	default:
+		return false
+	}
+	return problem.Known(w, err)
+}
+
+// validatePatch запрещает очищать обязательные поля песни и возвращает
+// ошибку валидации со всеми некорректными полями
+func validatePatch(p models.SongPatch) error {
+	var invalid problem.ValidationError
+	if p.Title.Set && (p.Title.Null || p.Title.Value == "") {
+		invalid.Add(constants.FieldTitle, constants.ErrRequiredFields)
+	}
+	if p.Artist.Set && (p.Artist.Null || p.Artist.Value == "") {
+		invalid.Add(constants.FieldArtist, constants.ErrRequiredFields)
+	}
+	if p.Duration.Set && (p.Duration.Null || p.Duration.Value < 0) {
+		invalid.Add(constants.FieldDuration, constants.ErrDurationRequired)
+	}
+	if p.TrackNumber.Set && !p.TrackNumber.Null && p.TrackNumber.Value < 1 {
+		invalid.Add(constants.FieldTrackNumber, constants.ErrInvalidTrackNumber)
+	}
+	if p.DiscNumber.Set && !p.DiscNumber.Null && p.DiscNumber.Value < 1 {
+		invalid.Add(constants.FieldDiscNumber, constants.ErrInvalidTrackNumber)
+	}
+	if p.ReleaseDate.Set && !p.ReleaseDate.Null && p.ReleaseDate.Value != "" {
+		if _, err := time.Parse(constants.DateFormat, p.ReleaseDate.Value); err != nil {
+			invalid.Add(constants.FieldReleaseDate, constants.ErrInvalidReleaseDate)
+		}
+	}
+	return invalid.Err()
+}
+
+// @Summary Создать песню
+// @Description Создать новую песню. Текст, полученный из внешнего сервиса, разбивается по пустым строкам на куплеты
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param input body models.SimpleSongInput true "Данные песни"
+// @Success 201 {object} map[string]int "ID созданной песни"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня не найдена во внешнем сервисе"
+// @Failure 409 {object} models.Problem "Песня уже есть в библиотеке (при SONG_UNIQUE_KEY)"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Failure 502 {object} models.Problem "Некорректный ответ внешнего сервиса"
+// @Failure 504 {object} models.Problem "Превышено время ожидания внешнего сервиса"
+// @Security BearerAuth
+// @Router /songs [post]
+func (h *SongHandler) CreateSong(w http.ResponseWriter, r *http.Request) {
+	// Декодируем входящий JSON
+	var input models.SimpleSongInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrDecodingJSON, http.StatusBadRequest)
+		return
+	}
+
+	if err := requireFields(constants.QueryParamGroup, input.Group, constants.QueryParamSong, input.Song); err != nil {
+		h.logger.Print(constants.LogMissingFields)
+		problem.FromError(w, err, http.StatusBadRequest)
 		return
 	}
 
+	// Запрос к внешнему сервису для получения дополнительной информации
+	var song models.Song
+	if h.songInfo != nil {
+		detail, err := h.songInfo.GetSongInfo(r.Context(), input.Group, input.Song)
+		if err != nil {
+			h.logger.Printf(constants.LogError, constants.ErrFetchingSongInfo, err)
+			code, message, status := songInfoErrorResponse(err)
+			problem.Error(w, code, message, status)
+			return
+		}
+		song.ReleaseDate = detail.ReleaseDate
+		song.Text = detail.Text
+		song.Link = detail.Link
+	}
+
 	// Заполняем базовую информацию
 	song.Title = input.Song
 	song.Artist = input.Group
 
 	// Сохраняем в базу данных
-	id, err := h.repo.CreateSong(&song)
+	id, err := h.repo.CreateSong(r.Context(), &song)
+	if errors.Is(err, repository.ErrDuplicateSong) {
+		h.logger.Printf(constants.LogDuplicateSong, song.Title, song.Artist)
+		problem.FromError(w, err, http.StatusConflict)
+		return
+	}
 	if err != nil {
 		h.logger.Printf(constants.LogError, constants.ErrSavingSong, err)
-		http.Error(w, constants.ErrSavingSong, http.StatusInternalServerError)
+		problem.Internal(w, constants.ErrSavingSong)
 		return
 	}
 
@@ -281,6 +642,21 @@ func (h *SongHandler) CreateSong(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]int{"id": id})
 }
 
+// songInfoErrorResponse сопоставляет ошибку внешнего сервиса с кодом, сообщением
+// и статусом ответа клиенту. Текст исходной ошибки пишется только в журнал
+func songInfoErrorResponse(err error) (code, message string, status int) {
+	switch {
+	case errors.Is(err, songinfo.ErrNotFound):
+		return constants.CodeSongInfoNotFound, constants.ErrSongInfoNotFound, http.StatusNotFound
+	case errors.Is(err, songinfo.ErrTimeout):
+		return constants.CodeSongInfoTimeout, constants.ErrSongInfoTimeout, http.StatusGatewayTimeout
+	case errors.Is(err, songinfo.ErrMalformed):
+		return constants.CodeSongInfoMalformed, constants.ErrSongInfoMalformed, http.StatusBadGateway
+	default:
+		return constants.CodeSongInfoUnavailable, constants.ErrFetchingSongInfo, http.StatusBadGateway
+	}
+}
+
 // @Summary Получить информацию о песне
 // @Description Получить детальную информацию о песне по исполнителю и названию
 // @Tags songs
@@ -288,34 +664,35 @@ func (h *SongHandler) CreateSong(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param group query string true "Исполнитель"
 // @Param song query string true "Название песни"
+// @Param include_total query bool false "Возвращать total и total_pages" default(true)
 // @Success 200 {object} models.Song
-// @Failure 400 {string} string "Некорректные параметры запроса"
-// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Failure 400 {object} models.Problem "Некорректные параметры запроса"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
 // @Router /songs/info [get]
 func (h *SongHandler) GetSongInfo(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.logger.Printf(constants.LogMethodNotAllowed, r.Method, constants.HandlerGetSongInfo)
-		http.Error(w, constants.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
-		return
-	}
-
 	filter := models.SongFilter{
 		Title:   r.URL.Query().Get(constants.QueryParamSong),
 		Artist:  r.URL.Query().Get(constants.QueryParamGroup),
 		Page:    constants.DefaultPage,
 		PerPage: constants.DefaultPageSize,
 	}
+	var err error
+	if filter.IncludeTotal, err = queryBool(r, constants.QueryParamTotal, true); err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
 
 	if err := validateFilter(filter); err != nil {
 		h.logger.Printf(constants.LogValidationError, err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.FromError(w, err, http.StatusBadRequest)
 		return
 	}
 
 	response, err := h.repo.ListSongs(filter)
 	if err != nil {
 		h.logger.Printf(constants.LogError, constants.ErrGettingSongs, err)
-		http.Error(w, constants.ErrGettingSongs, http.StatusInternalServerError)
+		problem.Internal(w, constants.ErrGettingSongs)
 		return
 	}
 
@@ -323,7 +700,7 @@ func (h *SongHandler) GetSongInfo(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.Printf(constants.LogEncodingError, err)
-		http.Error(w, constants.ErrEncodingResponse, http.StatusInternalServerError)
+		problem.Internal(w, constants.ErrEncodingResponse)
 		return
 	}
 }
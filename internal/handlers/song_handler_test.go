@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+	"song-library/internal/songinfo"
+)
+
+func newTestLogger() *i18n.Logger {
+	return i18n.NewLogger(log.New(io.Discard, "", 0))
+}
+
+// decodeProblem читает ответ об ошибке application/problem+json
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) models.Problem {
+	t.Helper()
+	if got := rec.Header().Get(constants.HeaderContentType); got != constants.ContentTypeProblem {
+		t.Fatalf("Content-Type = %q, want %q", got, constants.ContentTypeProblem)
+	}
+	var p models.Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	return p
+}
+
+// Ответ внешнего сервиса проверяется до обращения к репозиторию, поэтому
+// обработчику достаточно FakeProvider без базы данных
+func TestCreateSongSongInfoErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"песня не найдена", nil, http.StatusNotFound, constants.CodeSongInfoNotFound},
+		{"таймаут", songinfo.ErrTimeout, http.StatusGatewayTimeout, constants.CodeSongInfoTimeout},
+		{"некорректный ответ", songinfo.ErrMalformed, http.StatusBadGateway, constants.CodeSongInfoMalformed},
+		{"сервис недоступен", songinfo.ErrUnavailable, http.StatusBadGateway, constants.CodeSongInfoUnavailable},
+		{"обернутая ошибка", errors.Join(songinfo.ErrTimeout, errors.New("i/o timeout")),
+			http.StatusGatewayTimeout, constants.CodeSongInfoTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := songinfo.NewFakeProvider()
+			provider.Add("Muse", "Hysteria", models.SongDetail{Text: "It's bugging me"})
+			if tt.err != nil {
+				provider.FailWith(tt.err)
+			}
+			handler := NewSongHandler(nil, newTestLogger(), provider, false)
+
+			body := `{"group":"Muse","song":"Uprising"}`
+			if tt.err != nil {
+				body = `{"group":"Muse","song":"Hysteria"}`
+			}
+			rec := httptest.NewRecorder()
+			handler.CreateSong(rec, httptest.NewRequest(http.MethodPost, "/api/songs", strings.NewReader(body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if p := decodeProblem(t, rec); p.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", p.Code, tt.wantCode)
+			}
+			if provider.Calls() != 1 {
+				t.Errorf("provider calls = %d, want 1", provider.Calls())
+			}
+		})
+	}
+}
+
+func TestCreateSongValidation(t *testing.T) {
+	provider := songinfo.NewFakeProvider()
+	handler := NewSongHandler(nil, newTestLogger(), provider, false)
+
+	rec := httptest.NewRecorder()
+	handler.CreateSong(rec, httptest.NewRequest(http.MethodPost, "/api/songs", strings.NewReader(`{"group":"Muse"}`)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	p := decodeProblem(t, rec)
+	if p.Code != constants.CodeValidationFailed || len(p.Errors) != 1 || p.Errors[0].Field != constants.QueryParamSong {
+		t.Errorf("problem = %+v", p)
+	}
+	if provider.Calls() != 0 {
+		t.Errorf("provider calls = %d, want 0", provider.Calls())
+	}
+}
+
+// Сообщения полей формируются из ключа и аргументов на языке ответа
+func TestCreateSongValidationLocalized(t *testing.T) {
+	handler := NewSongHandler(nil, newTestLogger(), songinfo.NewFakeProvider(), false)
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set(constants.HeaderContentLanguage, "en")
+	handler.CreateSong(rec, httptest.NewRequest(http.MethodPost, "/api/songs", strings.NewReader(`{}`)))
+
+	p := decodeProblem(t, rec)
+	if len(p.Errors) != 2 {
+		t.Fatalf("errors = %+v, want 2", p.Errors)
+	}
+	for _, fe := range p.Errors {
+		if fe.Message != "title and artist are required" {
+			t.Errorf("%s: message = %q", fe.Field, fe.Message)
+		}
+	}
+	if want := "validation failed"; p.Detail != want {
+		t.Errorf("detail = %q, want %q", p.Detail, want)
+	}
+}
+
+func TestSongInfoErrorResponse(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantCode   string
+		wantStatus int
+	}{
+		{songinfo.ErrNotFound, constants.CodeSongInfoNotFound, http.StatusNotFound},
+		{songinfo.ErrTimeout, constants.CodeSongInfoTimeout, http.StatusGatewayTimeout},
+		{songinfo.ErrMalformed, constants.CodeSongInfoMalformed, http.StatusBadGateway},
+		{songinfo.ErrUnavailable, constants.CodeSongInfoUnavailable, http.StatusBadGateway},
+		{errors.New("connection reset"), constants.CodeSongInfoUnavailable, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		code, _, status := songInfoErrorResponse(tt.err)
+		if code != tt.wantCode || status != tt.wantStatus {
+			t.Errorf("songInfoErrorResponse(%v) = %q, %d, want %q, %d", tt.err, code, status, tt.wantCode, tt.wantStatus)
+		}
+	}
+}
+
+// include_total разбирается так же, как в списке песен, до обращения к репозиторию
+func TestGetSongInfoIncludeTotal(t *testing.T) {
+	handler := NewSongHandler(nil, newTestLogger(), nil, false)
+
+	rec := httptest.NewRecorder()
+	handler.GetSongInfo(rec, httptest.NewRequest(http.MethodGet, "/api/songs/info?group=Muse&song=Uprising&include_total=maybe", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	p := decodeProblem(t, rec)
+	if len(p.Errors) != 1 || p.Errors[0].Field != constants.QueryParamTotal {
+		t.Errorf("problem = %+v", p)
+	}
+}
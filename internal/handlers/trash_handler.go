@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"song-library/internal/constants"
+	"song-library/internal/problem"
+	"song-library/internal/repository"
+)
+
+// @Summary Получить корзину
+// @Description Удаленные песни, недавно удаленные первыми. Песни окончательно удаляются
+// @Description по истечении срока хранения TRASH_RETENTION
+// @Tags songs
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param per_page query int false "Количество элементов на странице" default(10) maximum(100)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} models.Problem "Ошибка валидации"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/trash [get]
+func (h *SongHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.repo.ListTrash(page, perPage)
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrGettingTrash, err)
+		problem.Internal(w, constants.ErrGettingTrash)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
+
+// @Summary Восстановить песню из корзины
+// @Description Возвращает удаленную песню вместе с ее куплетами
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Версия песни"
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня не найдена в корзине"
+// @Failure 409 {object} models.Problem "Песня с таким названием и исполнителем уже есть (при SONG_UNIQUE_KEY)"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id}/restore [post]
+func (h *SongHandler) RestoreSong(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	song, err := h.repo.RestoreSong(r.Context(), id)
+	switch {
+	case errors.Is(err, repository.ErrSongNotInTrash):
+		h.logger.Printf(constants.LogSongNotInTrash, id)
+		problem.FromError(w, err, http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrDuplicateSong):
+		h.logger.Printf(constants.LogError, constants.ErrRestoringSong, err)
+		problem.FromError(w, err, http.StatusConflict)
+		return
+	case err != nil:
+		h.logger.Printf(constants.LogError, constants.ErrRestoringSong, err)
+		problem.Internal(w, constants.ErrRestoringSong)
+		return
+	}
+
+	h.logger.Printf(constants.LogSuccessRestore, id)
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	w.Header().Set(constants.HeaderETag, etag(song.Version))
+	if err := json.NewEncoder(w).Encode(song); err != nil {
+		h.logger.Printf(constants.LogEncodingError, err)
+	}
+}
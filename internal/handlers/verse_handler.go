@@ -2,20 +2,25 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
-	"log"
 	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/lyrics"
+	"song-library/internal/models"
+	"song-library/internal/problem"
 	"song-library/internal/repository"
 )
 
 type VerseHandler struct {
 	repo   *repository.VerseRepository
-	logger *log.Logger
+	logger *i18n.Logger
 }
 
-func NewVerseHandler(repo *repository.VerseRepository, logger *log.Logger) *VerseHandler {
+func NewVerseHandler(repo *repository.VerseRepository, logger *i18n.Logger) *VerseHandler {
 	return &VerseHandler{repo: repo, logger: logger}
 }
 
@@ -24,24 +29,20 @@ func NewVerseHandler(repo *repository.VerseRepository, logger *log.Logger) *Vers
 // @Tags verses
 // @Accept json
 // @Produce json
-// @Param song_id query int true "ID песни"
+// @Param id path int true "ID песни"
 // @Param page query int false "Номер страницы" default(1)
 // @Param page_size query int false "Количество элементов на странице" default(10) maximum(50)
+// @Param cursor query string false "Курсор из заголовка X-Next-Cursor, пустое значение - первая страница. Заменяет page"
 // @Success 200 {array} models.Verse
-// @Failure 400 {string} string "Некорректный ID песни"
-// @Failure 500 {string} string "Внутренняя ошибка сервера"
-// @Router /verses [get]
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы в курсорном режиме"
+// @Failure 400 {object} models.Problem "Некорректный ID песни"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/{id}/verses [get]
 func (h *VerseHandler) GetVerses(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.logger.Printf(constants.LogMethodNotAllowed, r.Method, constants.HandlerGetVerses)
-		http.Error(w, constants.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
-		return
-	}
-
-	songID, err := strconv.Atoi(r.URL.Query().Get(constants.QueryParamSongID))
+	songID, err := resourceID(r, constants.QueryParamSongID)
 	if err != nil {
-		h.logger.Printf(constants.LogInvalidID, r.URL.Query().Get(constants.QueryParamSongID))
-		http.Error(w, constants.ErrInvalidID, http.StatusBadRequest)
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
 		return
 	}
 
@@ -61,13 +62,340 @@ func (h *VerseHandler) GetVerses(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if r.URL.Query().Has(constants.QueryParamCursor) {
+		h.getVersesAfter(w, r, songID, pageSize)
+		return
+	}
+
 	verses, err := h.repo.GetVerses(songID, page, pageSize)
 	if err != nil {
 		h.logger.Printf(constants.LogGettingVerses, songID, err)
-		http.Error(w, constants.ErrGettingVerses, http.StatusInternalServerError)
+		problem.Internal(w, constants.ErrGettingVerses)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	json.NewEncoder(w).Encode(verses)
+}
+
+// getVersesAfter отдает страницу куплетов в курсорном режиме,
+// курсор следующей страницы передается в заголовке X-Next-Cursor
+func (h *VerseHandler) getVersesAfter(w http.ResponseWriter, r *http.Request, songID, pageSize int) {
+	verses, next, err := h.repo.ListVersesAfter(songID, r.URL.Query().Get(constants.QueryParamCursor), pageSize)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Printf(constants.LogGettingVerses, songID, err)
+		problem.Internal(w, constants.ErrGettingVerses)
 		return
 	}
 
+	if next != "" {
+		w.Header().Set(constants.HeaderNextCursor, next)
+	}
 	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
 	json.NewEncoder(w).Encode(verses)
 }
+
+// @Summary Добавить куплет
+// @Description Добавить куплет в песню. Тип задается названием из verse_types (verse, chorus, bridge, intro, outro, pre_chorus).
+// @Description Если verse_number не указан, куплет добавляется в конец, иначе последующие куплеты сдвигаются
+// @Tags verses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param verse body models.VerseInput true "Данные куплета"
+// @Success 201 {object} map[string]int "ID созданного куплета"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id}/verses [post]
+func (h *VerseHandler) CreateVerse(w http.ResponseWriter, r *http.Request) {
+	input, ok := h.decodeVerseInput(w, r)
+	if !ok {
+		return
+	}
+	if r.PathValue(constants.PathParamID) != "" {
+		songID, err := resourceID(r, constants.QueryParamSongID)
+		if err != nil {
+			h.logger.Printf(constants.LogInvalidID, err)
+			problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+			return
+		}
+		input.SongID = songID
+	}
+	if input.SongID < 1 {
+		h.logger.Printf(constants.LogInvalidID, input.SongID)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	if input.VerseNumber < 0 {
+		problem.FromError(w, problem.Invalid(constants.FieldVerseNumber, constants.ErrInvalidVerseNumber), http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.repo.CreateVerse(r.Context(), input)
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrCreatingVerse, err)
+		h.writeRepoError(w, err, constants.ErrCreatingVerse)
+		return
+	}
+
+	h.logger.Printf(constants.LogSuccessCreateVerse, id, input.SongID)
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]int{"id": id})
+}
+
+// @Summary Обновить куплет
+// @Description Изменить тип и текст куплета
+// @Tags verses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID куплета"
+// @Param verse body models.VerseInput true "Данные куплета"
+// @Success 200 "Куплет успешно обновлен"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Куплет не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /verses/{id} [put]
+func (h *VerseHandler) UpdateVerse(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	input, ok := h.decodeVerseInput(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.UpdateVerse(r.Context(), id, input); err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrUpdatingVerse, err)
+		h.writeRepoError(w, err, constants.ErrUpdatingVerse)
+		return
+	}
+
+	h.logger.Printf(constants.LogSuccessUpdateVerse, id)
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Переместить куплет
+// @Description Перенести куплет на новую позицию внутри песни, остальные куплеты перенумеровываются
+// @Tags verses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID куплета"
+// @Param move body models.VerseMove true "Новая позиция"
+// @Success 200 "Куплет успешно перемещен"
+// @Failure 400 {object} models.Problem "Некорректные данные"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Куплет не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /verses/{id}/position [put]
+func (h *VerseHandler) MoveVerse(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	var move models.VerseMove
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return
+	}
+	if move.Position < 1 {
+		problem.FromError(w, problem.Invalid(constants.FieldVerseNumber, constants.ErrInvalidVerseNumber), http.StatusBadRequest)
+		return
+	}
+
+	position, err := h.repo.MoveVerse(r.Context(), id, move.Position)
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrMovingVerse, err)
+		h.writeRepoError(w, err, constants.ErrMovingVerse)
+		return
+	}
+
+	h.logger.Printf(constants.LogSuccessMoveVerse, id, position)
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Удалить куплет
+// @Description Удалить куплет по ID, последующие куплеты сдвигаются
+// @Tags verses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID куплета"
+// @Success 204 "Куплет успешно удален"
+// @Failure 400 {object} models.Problem "Некорректный ID"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Куплет не найден"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /verses/{id} [delete]
+func (h *VerseHandler) DeleteVerse(w http.ResponseWriter, r *http.Request) {
+	id, err := resourceID(r, constants.QueryParamID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.DeleteVerse(r.Context(), id); err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrDeletingVerse, err)
+		h.writeRepoError(w, err, constants.ErrDeletingVerse)
+		return
+	}
+
+	h.logger.Printf(constants.LogSuccessDeleteVerse, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Получить текст песни
+// @Description Текст песни из куплетов: lrc и srt с временными метками для караоке, txt - куплеты через пустую строку.
+// @Description Для lrc и srt у каждого куплета должно быть время начала
+// @Tags verses
+// @Produce plain
+// @Param id path int true "ID песни"
+// @Param format query string false "Формат текста" Enums(lrc, srt, txt) default(lrc)
+// @Success 200 {string} string "Текст песни"
+// @Failure 400 {object} models.Problem "Некорректный ID или формат"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 422 {object} models.Problem "У куплетов нет временных меток"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics [get]
+func (h *VerseHandler) GetLyrics(w http.ResponseWriter, r *http.Request) {
+	songID, err := resourceID(r, constants.QueryParamSongID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get(constants.QueryParamFormat)
+	if format == "" {
+		format = constants.LyricsFormatLRC
+	}
+
+	blocks, duration, err := h.repo.GetLyrics(songID)
+	if err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrGettingLyrics, err)
+		h.writeRepoError(w, err, constants.ErrGettingLyrics)
+		return
+	}
+
+	var b strings.Builder
+	err = lyrics.Render(&b, format, blocks, duration*1000)
+	if errors.Is(err, lyrics.ErrNoTimings) {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeText)
+	w.Write([]byte(b.String()))
+}
+
+// @Summary Загрузить текст песни в формате LRC
+// @Description Заменяет все куплеты песни строками файла LRC: каждая строка с меткой становится куплетом,
+// @Description окончанием считается метка следующей строки. Метки должны идти по порядку и не выходить за длительность песни.
+// @Description Текст песни (поле text) собирается из новых куплетов
+// @Tags verses
+// @Accept plain
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lyrics body string true "Содержимое файла LRC"
+// @Success 200 {object} map[string]int "Количество созданных куплетов"
+// @Failure 400 {object} models.Problem "Некорректный файл или временные метки"
+// @Failure 401 {object} models.Problem "Требуется аутентификация"
+// @Failure 403 {object} models.Problem "Недостаточно прав"
+// @Failure 404 {object} models.Problem "Песня не найдена"
+// @Failure 500 {object} models.Problem "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Router /songs/{id}/lyrics [put]
+func (h *VerseHandler) UploadLyrics(w http.ResponseWriter, r *http.Request) {
+	songID, err := resourceID(r, constants.QueryParamSongID)
+	if err != nil {
+		h.logger.Printf(constants.LogInvalidID, err)
+		problem.Error(w, constants.CodeInvalidID, constants.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, constants.LyricsMaxBodySize)
+	blocks, err := lyrics.ParseLRC(r.Body)
+	if err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.ReplaceLyrics(r.Context(), songID, blocks); err != nil {
+		h.logger.Printf(constants.LogError, constants.ErrUploadingLyrics, err)
+		h.writeRepoError(w, err, constants.ErrUploadingLyrics)
+		return
+	}
+
+	h.logger.Printf(constants.LogSuccessLyrics, songID, len(blocks))
+	w.Header().Set(constants.HeaderContentType, constants.HeaderContentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]int{"verses": len(blocks)})
+}
+
+func (h *VerseHandler) decodeVerseInput(w http.ResponseWriter, r *http.Request) (models.VerseInput, bool) {
+	var input models.VerseInput
+
+	contentType := r.Header.Get(constants.HeaderContentType)
+	if contentType != constants.HeaderContentTypeJSON {
+		h.logger.Printf(constants.LogInvalidContentType, contentType)
+		problem.Error(w, constants.CodeInvalidContentType, constants.ErrInvalidContentType, http.StatusBadRequest)
+		return input, false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576) // 1MB limit
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Printf(constants.LogDecodingError, err)
+		problem.Error(w, constants.CodeInvalidJSON, constants.ErrInvalidData, http.StatusBadRequest)
+		return input, false
+	}
+
+	if strings.TrimSpace(input.Content) == "" {
+		h.logger.Print(constants.LogMissingFields)
+		problem.FromError(w, problem.Invalid(constants.FieldContent, constants.ErrVerseContentRequired), http.StatusBadRequest)
+		return input, false
+	}
+	// порядок относительно других куплетов проверяет репозиторий
+	timings := []lyrics.Block{{StartMs: input.StartMs, EndMs: input.EndMs}}
+	if err := lyrics.ValidateTimings(timings, 0); err != nil {
+		h.logger.Printf(constants.LogValidationError, err)
+		problem.FromError(w, err, http.StatusBadRequest)
+		return input, false
+	}
+	return input, true
+}
+
+// writeRepoError сопоставляет ошибки репозитория со статусами ответа
+func (h *VerseHandler) writeRepoError(w http.ResponseWriter, err error, fallback string) {
+	if !problem.Known(w, err) {
+		problem.Internal(w, fallback)
+	}
+}
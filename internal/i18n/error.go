@@ -0,0 +1,41 @@
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Error - ошибка с ключом каталога и аргументами. Error() возвращает текст
+// на исходном языке, Localize - на любом поддерживаемом
+type Error struct {
+	Key  string
+	Args []any
+	err  error
+}
+
+// Errorf - замена fmt.Errorf для сообщений каталога. Аргументы %w доступны
+// через errors.Is и errors.As
+func Errorf(key string, args ...any) error {
+	return &Error{Key: key, Args: args, err: fmt.Errorf(key, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap возвращает ошибки, переданные глаголом %w
+func (e *Error) Unwrap() []error {
+	switch wrapped := e.err.(type) {
+	case interface{ Unwrap() []error }:
+		return wrapped.Unwrap()
+	case interface{ Unwrap() error }:
+		return []error{wrapped.Unwrap()}
+	}
+	return nil
+}
+
+// Localize форматирует сообщение на языке tag
+func (e *Error) Localize(tag language.Tag) string {
+	return Sprintf(tag, e.Key, e.Args...)
+}
@@ -0,0 +1,155 @@
+// Package i18n переводит сообщения API и журнала. Исходный язык - русский:
+// ключами каталога служат сообщения из constants/errors.go, переводы на
+// английский лежат в messages_en.go. Сообщение с аргументами передается
+// ключом и аргументами (Errorf) и форматируется сразу на нужном языке
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"song-library/internal/constants"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Source - исходный язык сообщений
+var Source = language.Russian
+
+var (
+	supported = []language.Tag{language.Russian, language.English}
+	matcher   = language.NewMatcher(supported)
+
+	// verbPattern находит глаголы форматирования fmt, включая %%
+	verbPattern = regexp.MustCompile(`%[-+# 0]*(\d+|\*)?(\.(\d+|\*)?)?[a-zA-Z%]`)
+
+	printers = make(map[language.Tag]*message.Printer, len(supported))
+)
+
+func init() {
+	builder := catalog.NewBuilder(catalog.Fallback(Source))
+	for key, translation := range english {
+		builder.SetString(language.Russian, key, stringVerbs(key))
+		builder.SetString(language.English, key, stringVerbs(translation))
+	}
+	for _, tag := range supported {
+		printers[tag] = message.NewPrinter(tag, message.Catalog(builder))
+	}
+}
+
+// Parse возвращает поддерживаемый язык по коду ru или en
+func Parse(code string) (language.Tag, error) {
+	tag, err := language.Parse(code)
+	if err == nil {
+		for _, candidate := range supported {
+			if base, _ := tag.Base(); candidate == language.Make(base.String()) {
+				return candidate, nil
+			}
+		}
+	}
+	return Source, fmt.Errorf(constants.ErrUnknownLanguage, code)
+}
+
+// Match выбирает язык ответа по заголовку Accept-Language, если ни один
+// из запрошенных языков не поддерживается - исходный язык
+func Match(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Source
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Source
+	}
+	return supported[index]
+}
+
+// Sprintf форматирует сообщение каталога format на языке tag. Строковые
+// аргументы - ключи каталога и ошибки тоже переводятся
+func Sprintf(tag language.Tag, format string, args ...any) string {
+	if tag == Source {
+		return fmt.Sprintf(format, args...)
+	}
+	args = translateArgs(tag, args)
+	if _, ok := english[format]; !ok {
+		return fmt.Sprintf(format, args...)
+	}
+
+	verbs := argVerbs(format)
+	if len(verbs) != len(args) {
+		return fmt.Sprintf(format, args...)
+	}
+	formatted := make([]any, len(args))
+	for i, arg := range args {
+		formatted[i] = fmt.Sprintf(strings.Replace(verbs[i], "w", "v", 1), arg)
+	}
+	return printers[tag].Sprintf(format, formatted...)
+}
+
+// Translate переводит на язык tag сообщение каталога без аргументов. Текст,
+// не являющийся ключом каталога, возвращается без изменений
+func Translate(tag language.Tag, text string) string {
+	if tag == Source || len(argVerbs(text)) > 0 {
+		return text
+	}
+	if _, ok := english[text]; !ok {
+		return text
+	}
+	return printers[tag].Sprintf(text)
+}
+
+// Localizer - сообщение, которое само форматируется на нужном языке
+type Localizer interface {
+	Localize(tag language.Tag) string
+}
+
+// Localize возвращает текст ошибки err на языке tag: ошибки Errorf и другие
+// Localizer форматируются по ключу и аргументам, ошибки с текстом-ключом
+// каталога переводятся, остальные возвращаются без изменений
+func Localize(tag language.Tag, err error) string {
+	if l, ok := err.(Localizer); ok {
+		return l.Localize(tag)
+	}
+	return Translate(tag, err.Error())
+}
+
+// translateArgs переводит строковые аргументы и ошибки, не изменяя исходный срез
+func translateArgs(tag language.Tag, args []any) []any {
+	translated := make([]any, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case string:
+			translated[i] = Translate(tag, value)
+		case error:
+			translated[i] = Localize(tag, value)
+		default:
+			translated[i] = arg
+		}
+	}
+	return translated
+}
+
+// argVerbs возвращает глаголы форматирования, которым соответствуют аргументы
+func argVerbs(format string) []string {
+	var verbs []string
+	for _, verb := range verbPattern.FindAllString(format, -1) {
+		if verb != "%%" {
+			verbs = append(verbs, verb)
+		}
+	}
+	return verbs
+}
+
+// stringVerbs заменяет глаголы форматирования на %s: аргументы передаются
+// в каталог уже отформатированными, чтобы числа не получали разделителей разрядов
+func stringVerbs(format string) string {
+	return verbPattern.ReplaceAllStringFunc(format, func(verb string) string {
+		if verb == "%%" {
+			return verb
+		}
+		return "%s"
+	})
+}
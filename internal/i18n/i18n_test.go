@@ -0,0 +1,106 @@
+package i18n
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"testing"
+
+	"song-library/internal/constants"
+
+	"golang.org/x/text/language"
+)
+
+// Каждое сообщение из constants/errors.go должно иметь перевод на английский
+// с теми же глаголами форматирования
+func TestCatalogueComplete(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "../constants/errors.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse errors.go: %v", err)
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				lit, ok := value.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				key, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatalf("%s: %v", name.Name, err)
+				}
+				translation, ok := english[key]
+				if !ok {
+					t.Errorf("%s: нет перевода на английский", name.Name)
+					continue
+				}
+				if !slices.Equal(argVerbs(key), argVerbs(translation)) {
+					t.Errorf("%s: глаголы %v, в переводе %v", name.Name, argVerbs(key), argVerbs(translation))
+				}
+			}
+		}
+	}
+}
+
+func TestErrorfLocalize(t *testing.T) {
+	cause := errors.New(constants.ErrSongNotFound)
+	err := Errorf(constants.ErrDBConnection, cause)
+
+	if got, want := err.Error(), "ошибка подключения к БД: песня не найдена"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := Localize(language.English, err), "failed to connect to DB: song not found"; got != want {
+		t.Errorf("Localize(en) = %q, want %q", got, want)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is(err, cause) = false")
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	tests := []struct {
+		name   string
+		tag    language.Tag
+		format string
+		args   []any
+		want   string
+	}{
+		{"исходный язык", language.Russian, constants.ErrImportFieldCount, []any{12000, 3},
+			"ожидалось 12000 значений, получено 3"},
+		{"числа без разделителей разрядов", language.English, constants.ErrImportFieldCount, []any{12000, 3},
+			"expected 12000 values, got 3"},
+		{"аргумент - ключ каталога", language.English, constants.LogError, []any{constants.ErrExportFailed, errors.New("eof")},
+			"failed to export songs: eof"},
+		{"вложенная ошибка с аргументами", language.English, constants.LogError,
+			[]any{constants.ErrExportFailed, Errorf(constants.ErrInvalidQueryParam, "page")},
+			"failed to export songs: invalid value of parameter page"},
+		{"сообщение не из каталога", language.English, "%s!", []any{constants.ErrSongNotFound}, "song not found!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sprintf(tt.tag, tt.format, tt.args...); got != tt.want {
+				t.Errorf("Sprintf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := Translate(language.English, constants.ErrSongNotFound); got != "song not found" {
+		t.Errorf("Translate(key) = %q", got)
+	}
+	// уже отформатированный текст не разбирается обратно по ключам
+	formatted := Errorf(constants.ErrInvalidQueryParam, "page").Error()
+	if got := Translate(language.English, formatted); got != formatted {
+		t.Errorf("Translate(formatted) = %q, want %q", got, formatted)
+	}
+}
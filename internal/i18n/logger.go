@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"golang.org/x/text/language"
+)
+
+// Logger - log.Logger, который пишет сообщения на языке журнала
+type Logger struct {
+	*log.Logger
+	lang language.Tag
+}
+
+// NewLogger оборачивает logger, сообщения пишутся на исходном языке,
+// пока не задан другой через SetLanguage
+func NewLogger(logger *log.Logger) *Logger {
+	return &Logger{Logger: logger, lang: Source}
+}
+
+// SetLanguage задает язык журнала. Вызывается при запуске, до того как
+// логгер начнут использовать другие горутины
+func (l *Logger) SetLanguage(lang language.Tag) {
+	l.lang = lang
+}
+
+// Translate переводит сообщение на язык журнала
+func (l *Logger) Translate(text string) string {
+	return Translate(l.lang, text)
+}
+
+func (l *Logger) Printf(format string, v ...any) {
+	l.Output(2, Sprintf(l.lang, format, v...))
+}
+
+func (l *Logger) Print(v ...any) {
+	l.Output(2, fmt.Sprint(translateArgs(l.lang, v)...))
+}
+
+func (l *Logger) Println(v ...any) {
+	l.Output(2, fmt.Sprintln(translateArgs(l.lang, v)...))
+}
+
+func (l *Logger) Fatal(v ...any) {
+	l.Output(2, fmt.Sprint(translateArgs(l.lang, v)...))
+	os.Exit(1)
+}
@@ -0,0 +1,224 @@
+package i18n
+
+import "song-library/internal/constants"
+
+// english - английские переводы сообщений из constants/errors.go. Глаголы
+// форматирования должны идти в том же порядке, что и в русском сообщении
+var english = map[string]string{
+	constants.ErrInvalidID:            "invalid id",
+	constants.ErrSongNotFound:         "song not found",
+	constants.ErrArtistNotFound:       "artist not found",
+	constants.ErrAlbumNotFound:        "album not found",
+	constants.ErrGenreNotFound:        "genre not found",
+	constants.ErrDuplicateSong:        "a song with this title and artist is already in the library",
+	constants.ErrMergeSameSong:        "a song cannot be merged with itself",
+	constants.ErrDuplicatesExist:      "the library contains duplicates, merge them before enabling SONG_UNIQUE_KEY",
+	constants.ErrInvalidDuplicateID:   "duplicate ID is not specified",
+	constants.ErrGettingDuplicates:    "failed to find duplicates",
+	constants.ErrMergingSongs:         "failed to merge songs",
+	constants.ErrUniqueKeySetup:       "failed to set up the song unique key",
+	constants.ErrSongNotInTrash:       "song not found in trash",
+	constants.ErrGettingTrash:         "failed to get trash",
+	constants.ErrRestoringSong:        "failed to restore song",
+	constants.ErrPurgingTrash:         "failed to purge trash",
+	constants.ErrRevisionNotFound:     "song revision not found",
+	constants.ErrGettingHistory:       "failed to get song change history",
+	constants.ErrRevertingSong:        "failed to revert song to revision",
+	constants.ErrInvalidRevision:      "invalid revision number",
+	constants.ErrPlaylistNotFound:     "playlist not found",
+	constants.ErrPlaylistItemNotFound: "playlist item not found",
+	constants.ErrInvalidPlaylistOrder: "the new order must contain every playlist item exactly once",
+	constants.ErrPlaylistNameRequired: "playlist name is required",
+	constants.ErrInvalidPosition:      "position must be greater than 0",
+	constants.ErrInvalidSongID:        "song ID is not specified",
+	constants.ErrGettingPlaylists:     "failed to get playlists",
+	constants.ErrSavingPlaylist:       "failed to save playlist",
+	constants.ErrDeletingPlaylist:     "failed to delete playlist",
+	constants.ErrChangingPlaylist:     "failed to change playlist items",
+	constants.ErrAPIKeyNotFound:       "API key not found or revoked",
+	constants.ErrAPIKeyExists:         "an API key with this name already exists",
+	constants.ErrAPIKeyCommandFailed:  "apikey command failed",
+	constants.ErrUnauthorized:         "authentication required",
+	constants.ErrInvalidCredentials:   "invalid API key or token",
+	constants.ErrAuthenticating:       "failed to verify credentials",
+	constants.ErrLoadingJWTKey:        "failed to load JWT public key",
+	constants.ErrInvalidPEM:           "file does not contain a PEM-encoded RSA public key",
+	constants.ErrLoadingPolicy:        "failed to load access policy",
+	constants.ErrUnknownRole:          "unknown role %q for %s, expected viewer, editor or admin",
+	constants.ErrForbidden:            "insufficient permissions: role %s required",
+	constants.ErrTooManyRequests:      "too many requests, try again later",
+	constants.ErrRouteNotFound:        "route not found",
+	constants.ErrMethodNotAllowed:     "method not allowed for this route",
+	constants.ErrValidationFailed:     "validation failed",
+	constants.ErrUnknownLanguage:      "unsupported language %q, expected ru or en",
+	constants.ErrAPIKeyRepoCreate:     "failed to create api key repository",
+	constants.ErrGettingCatalog:       "failed to get catalog",
+	constants.ErrInvalidReleaseYear:   "release year must be greater than 0",
+	constants.ErrEmptyArtistName:      "artist name cannot be empty",
+	constants.ErrInvalidData:          "invalid data",
+	constants.ErrRequiredFields:       "title and artist are required",
+	constants.ErrDurationRequired:     "duration cannot be empty or negative",
+	constants.ErrInvalidReleaseDate:   "release date must be in YYYY-MM-DD format",
+	constants.ErrPreconditionFailed:   "the song has been modified, fetch the current version and retry",
+	constants.ErrPreconditionRequired: "the If-Match header is required to modify a song",
+	constants.ErrInvalidPatchType:     "invalid Content-Type, expected application/merge-patch+json or application/json",
+	constants.ErrGettingSongs:         "failed to get song list",
+	constants.ErrGettingSong:          "failed to get song",
+	constants.ErrSearchingSongs:       "failed to search songs",
+	constants.ErrSearchQueryRequired:  "search query is required",
+	constants.ErrDeletingSong:         "failed to delete song",
+	constants.ErrUpdatingSong:         "failed to update song",
+	constants.ErrCreatingSong:         "failed to create song",
+	constants.ErrFetchingSongInfo:     "failed to get song info",
+	constants.ErrSongInfoNotFound:     "song info not found in the external service",
+	constants.ErrSongInfoTimeout:      "external service timed out",
+	constants.ErrSongInfoMalformed:    "malformed external service response",
+	constants.ErrSongInfoUnavailable:  "external service unavailable",
+	constants.ErrSongInfoStatus:       "unexpected external service response status: %d",
+	constants.ErrInvalidPage:          "page must be greater than 0",
+	constants.ErrInvalidQueryParam:    "invalid value of parameter %s",
+	constants.ErrInvalidPerPage:       "items per page must be between 1 and 100",
+	constants.ErrInvalidSortField:     "sorting by field %s is not supported",
+	constants.ErrInvalidSortOrder:     "sort order must be asc or desc",
+	constants.ErrUnknownImportFormat:  "unknown import format: %s",
+	constants.ErrImportMissingColumn:  "required column %s is missing from CSV",
+	constants.ErrImportFieldCount:     "expected %d values, got %d",
+	constants.ErrImportRow:            "invalid row: %v",
+	constants.ErrImportDuplicate:      "song is already in the library",
+	constants.ErrImportDuplicateRow:   "song duplicates row %d",
+	constants.ErrImportInvalidInput:   "invalid import file",
+	constants.ErrImportFailed:         "import failed",
+	constants.ErrImportBatch:          "failed to save batch: %v",
+	constants.ErrImportUsage:          "usage: import [-format csv|ndjson] [-dry-run] [-actor name] <file|->",
+	constants.ErrAPIKeyUsage:          "usage: apikey create [-role viewer|editor|admin] <name> | apikey list | apikey revoke <name>",
+	constants.ErrUnknownExportFormat:  "unknown export format: %s",
+	constants.ErrExportFuzzy:          "export does not support fuzzy search",
+	constants.ErrExportFailed:         "failed to export songs",
+	constants.ErrInvalidTimings:       "invalid verse timestamps",
+	constants.ErrTimingNegative:       "verse %d: time cannot be negative",
+	constants.ErrTimingOrder:          "verse %d: starts before the previous one ends",
+	constants.ErrTimingEnd:            "verse %d: end must be after start",
+	constants.ErrTimingDuration:       "verse %d: time exceeds the song duration",
+	constants.ErrInvalidLRC:           "LRC file contains no timestamped lines",
+	constants.ErrNoTimings:            "verses have no timestamps",
+	constants.ErrNoStartTime:          "verse %d has no start time",
+	constants.ErrNoEndTime:            "verse %d has no end time",
+	constants.ErrUnknownLyricsFormat:  "unknown lyrics format: %s",
+	constants.ErrGettingLyrics:        "failed to get song lyrics",
+	constants.ErrUploadingLyrics:      "failed to upload song lyrics",
+	constants.ErrInvalidTrackNumber:   "track and disc number must be greater than 0",
+	constants.ErrInvalidRange:         "parameter %s cannot be greater than %s",
+	constants.ErrInvalidCursor:        "invalid cursor",
+	constants.ErrCursorWithFuzzy:      "cursor pagination is not available for fuzzy search",
+	constants.ErrDecodingJSON:         "failed to decode json",
+	constants.ErrEncodingResponse:     "failed to encode response",
+	constants.ErrProcessingSongInfo:   "failed to process song info",
+	constants.ErrSavingSong:           "failed to save song",
+	constants.ErrGettingVerses:        "failed to get verses",
+	constants.ErrVerseNotFound:        "verse not found",
+	constants.ErrUnknownVerseType:     "unknown verse type",
+	constants.ErrVerseContentRequired: "verse text is required",
+	constants.ErrInvalidVerseNumber:   "verse number must be greater than 0",
+	constants.ErrCreatingVerse:        "failed to create verse",
+	constants.ErrUpdatingVerse:        "failed to update verse",
+	constants.ErrMovingVerse:          "failed to move verse",
+	constants.ErrDeletingVerse:        "failed to delete verse",
+	constants.ErrLoadingConfig:        "failed to load configuration",
+	constants.ErrServerSetup:          "failed to set up server",
+	constants.ErrMigratorInit:         "failed to initialize migrator",
+	constants.ErrMigrationUp:          "failed to apply migrations",
+	constants.ErrMigrationDown:        "failed to roll back migrations",
+	constants.ErrServerCritical:       "critical server error",
+	constants.ErrGracefulShutdown:     "graceful shutdown failed",
+	constants.ErrMissingEnvVar:        "required environment variable is missing: %s",
+	constants.ErrInvalidEnvVar:        "invalid value of environment variable %s: %s",
+	constants.ErrUnknownLimitRoute:    "unknown route %q in RATE_LIMIT_ROUTES, expected a pattern like \"GET /api/songs/{id}\"",
+	constants.ErrDBConnection:         "failed to connect to DB: %w",
+	constants.ErrAppInit:              "failed to initialize application",
+	constants.ErrAppRuntime:           "application runtime error",
+	constants.ErrAppShutdown:          "failed to shut down application",
+	constants.ErrReadingMigrationDir:  "failed to read migrations directory: %w",
+	constants.ErrReadingFile:          "failed to read file %s: %w",
+	constants.ErrExecutingMigration:   "migration %s failed: %w",
+	constants.ErrLoggerNil:            "logger cannot be nil",
+	constants.ErrMigrationDiraction:   "invalid migration direction: %s",
+	constants.ErrContextNil:           "nil context passed",
+	constants.ErrMigrationTableCheck:  "failed to check migrations table: %w",
+	constants.ErrTransactionStart:     "failed to begin transaction: %w",
+	constants.ErrTransactionCommit:    "failed to commit transaction: %w",
+	constants.ErrReadingQueryFile:     "failed to read query file %s: %w",
+	constants.ErrReadingDirectory:     "failed to read directory: %w",
+	constants.ErrSongRepoCreate:       "failed to create song repository",
+	constants.ErrVerseRepoCreate:      "failed to create verse repository",
+	constants.ErrCatalogRepoCreate:    "failed to create catalog repository",
+	constants.ErrPlaylistRepoCreate:   "failed to create playlist repository",
+	constants.ErrInvalidContentType:   "invalid Content-Type, expected application/json",
+
+	constants.LogInvalidID:               "invalid ID: %v",
+	constants.LogSongNotFound:            "song with ID %d not found",
+	constants.LogValidationError:         "filter validation error: %v",
+	constants.LogDecodingError:           "JSON decoding error: %v",
+	constants.LogMissingFields:           "required fields are missing",
+	constants.LogSuccessDelete:           "song with ID %d deleted",
+	constants.LogSuccessUpdate:           "song with ID %d updated",
+	constants.LogSuccessPatch:            "song with ID %d partially updated",
+	constants.LogSuccessMerge:            "song with ID %d merged with duplicate with ID %d",
+	constants.LogSuccessRestore:          "song with ID %d restored from trash",
+	constants.LogSongNotInTrash:          "song with ID %d not found in trash",
+	constants.LogTrashPurged:             "songs permanently deleted from trash: %d",
+	constants.LogSuccessRevert:           "song with ID %d reverted to revision %d",
+	constants.LogRevisionNotFound:        "revision %d of song with ID %d not found",
+	constants.LogDuplicateSong:           "song %q by %q is already in the library",
+	constants.LogVersionMismatch:         "version of song with ID %d does not match If-Match",
+	constants.LogIfMatchMissing:          "If-Match header is missing for song with ID %d",
+	constants.LogEncodingError:           "response encoding error: %v",
+	constants.LogDeadlineError:           "failed to extend request deadline: %v",
+	constants.LogGettingVerses:           "failed to get verses of song with ID %d: %v",
+	constants.LogVerseNotFound:           "verse with ID %d not found",
+	constants.LogImportFinished:          "import finished: created %d, skipped %d, failed %d, dry run: %t",
+	constants.LogExportAborted:           "export aborted after the response started: %v",
+	constants.LogSuccessAlbum:            "album with ID %d updated",
+	constants.LogSuccessFeaturing:        "featured artists of song with ID %d updated",
+	constants.LogSuccessCreateVerse:      "verse with ID %d created for song with ID %d",
+	constants.LogSuccessUpdateVerse:      "verse with ID %d updated",
+	constants.LogSuccessMoveVerse:        "verse with ID %d moved to position %d",
+	constants.LogSuccessDeleteVerse:      "verse with ID %d deleted",
+	constants.LogSuccessLyrics:           "synchronized lyrics uploaded for song with ID %d, verses: %d",
+	constants.LogSuccessPlaylist:         "playlist with ID %d saved",
+	constants.LogDeletedPlaylist:         "playlist with ID %d deleted",
+	constants.LogPlaylistItemAdded:       "playlist with ID %d: song with ID %d added at position %d",
+	constants.LogPlaylistItemRemove:      "playlist with ID %d: item with ID %d removed",
+	constants.LogPlaylistItemMoved:       "item with ID %d of playlist with ID %d moved to position %d",
+	constants.LogPlaylistReordered:       "items of playlist with ID %d reordered",
+	constants.LogAPIKeyCreated:           "API key %q created, save it - it will not be shown again:\n%s\n",
+	constants.LogAPIKeyRevoked:           "API key %q revoked\n",
+	constants.LogAPIKeyRow:               "%s\t%s\t%s\t%s\t%s\n",
+	constants.LogAuthFailed:              "credentials rejected for %s %s: %v",
+	constants.LogAccessDenied:            "client %q with role %s denied access to %s %s: role %s required",
+	constants.LogRateLimited:             "client %s exceeded the rate limit for %s %s",
+	constants.LogAuthDisabled:            "JWT is not configured, only API keys are accepted",
+	constants.LogError:                   "%s: %v",
+	constants.LogConfigLoaded:            "Configuration loaded",
+	constants.LogServerStarted:           "Server started on %s",
+	constants.LogSignalReceived:          "Signal received: %v",
+	constants.LogShutdownNotice:          "Shutdown notice received",
+	constants.LogServerStopped:           "Server stopped successfully",
+	constants.LogMigrationRollback:       "Migrations rolled back successfully. Original error: %v",
+	constants.LogMigrationFailure:        "%v (original error: %v)",
+	constants.LogMigrationStart:          "Starting migrations (%s)...",
+	constants.LogMigrationApply:          "apply",
+	constants.LogMigrationRollbackAction: "rollback",
+	constants.LogMigrationApplied:        "applied",
+	constants.LogMigrationRolledBack:     "rolled back",
+	constants.LogMigrationNotFound:       "No migration files found",
+	constants.LogMigrationProcess:        "%s migration: %s",
+	constants.LogMigrationPerTime:        "Migrations %s in %v",
+	constants.LogDBConnected:             "Connected to DB successfully",
+	constants.LogDBConnecting:            "Connecting to DB %s:%s...",
+	constants.LogMigrationSkipped:        "migration %s is already applied, skipping this version",
+	constants.LogReposInitialized:        "Repositories initialized successfully",
+	constants.LogServerSetupAddr:         "Setting up server at address: %s",
+	constants.LogSongInfoDisabled:        "External song info service is not configured, enrichment disabled",
+	constants.LogSongInfoRetry:           "retrying external service request (attempt %d): %v",
+	constants.LogInvalidContentType:      "invalid Content-Type received: %s",
+}
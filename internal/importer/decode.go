@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+)
+
+// Row - строка входного файла. Err заполняется, если строку не удалось разобрать
+type Row struct {
+	Line int
+	Song models.Song
+	Err  error
+}
+
+// decoder читает строки по одной и передает их в fn, ошибка fn прерывает чтение
+type decoder func(r io.Reader, fn func(Row) error) error
+
+func decoderFor(format string) (decoder, error) {
+	switch format {
+	case constants.ImportFormatCSV:
+		return decodeCSV, nil
+	case constants.ImportFormatNDJSON:
+		return decodeNDJSON, nil
+	default:
+		return nil, i18n.Errorf(constants.ErrUnknownImportFormat, format)
+	}
+}
+
+// csvColumns сопоставляет заголовки CSV полям песни, group и releaseDate -
+// синонимы в терминах внешнего сервиса и JSON API
+var csvColumns = map[string]func(s *models.Song, value string) error{
+	constants.ColumnTitle:          func(s *models.Song, v string) error { s.Title = v; return nil },
+	constants.ColumnArtist:         func(s *models.Song, v string) error { s.Artist = v; return nil },
+	constants.CSVColumnGroup:       func(s *models.Song, v string) error { s.Artist = v; return nil },
+	constants.ColumnAlbum:          func(s *models.Song, v string) error { s.Album = v; return nil },
+	constants.ColumnGenre:          func(s *models.Song, v string) error { s.Genre = v; return nil },
+	constants.ColumnReleaseDate:    func(s *models.Song, v string) error { s.ReleaseDate = v; return nil },
+	constants.CSVColumnReleaseDate: func(s *models.Song, v string) error { s.ReleaseDate = v; return nil },
+	constants.ColumnText:           func(s *models.Song, v string) error { s.Text = v; return nil },
+	constants.ColumnLink:           func(s *models.Song, v string) error { s.Link = v; return nil },
+	constants.ColumnDuration: func(s *models.Song, v string) error {
+		if v == "" {
+			return nil
+		}
+		duration, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.New(constants.ErrDurationRequired)
+		}
+		s.Duration = duration
+		return nil
+	},
+	constants.ColumnTrackNumber: func(s *models.Song, v string) (err error) {
+		s.TrackNumber, err = optionalNumber(v)
+		return err
+	},
+	constants.ColumnDiscNumber: func(s *models.Song, v string) (err error) {
+		s.DiscNumber, err = optionalNumber(v)
+		return err
+	},
+}
+
+// optionalNumber разбирает номер трека или диска, пустое значение - номер не задан
+func optionalNumber(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, errors.New(constants.ErrInvalidTrackNumber)
+	}
+	return &number, nil
+}
+
+// decodeCSV читает CSV с заголовком. Неизвестные колонки игнорируются,
+// title и artist (или group) обязательны
+func decodeCSV(r io.Reader, fn func(Row) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return err
+	}
+	setters := make([]func(*models.Song, string) error, len(header))
+	found := make(map[string]bool)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, constants.UTF8BOM))
+		setters[i] = csvColumns[name]
+		found[name] = true
+	}
+	if !found[constants.ColumnTitle] {
+		return i18n.Errorf(constants.ErrImportMissingColumn, constants.ColumnTitle)
+	}
+	if !found[constants.ColumnArtist] && !found[constants.CSVColumnGroup] {
+		return i18n.Errorf(constants.ErrImportMissingColumn, constants.ColumnArtist)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var row Row
+		var parseErr *csv.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			row.Line = parseErr.StartLine
+			row.Err = i18n.Errorf(constants.ErrImportRow, parseErr.Err)
+		case err != nil:
+			return err
+		case len(record) != len(header):
+			row.Line, _ = reader.FieldPos(0)
+			row.Err = i18n.Errorf(constants.ErrImportFieldCount, len(header), len(record))
+		default:
+			// позиции полей есть только у успешно разобранной записи
+			row.Line, _ = reader.FieldPos(0)
+			for i, value := range record {
+				if setters[i] == nil {
+					continue
+				}
+				if err := setters[i](&row.Song, strings.TrimSpace(value)); err != nil {
+					row.Err = err
+					break
+				}
+			}
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
+
+// ndjsonSong - строка NDJSON: поля models.SongUpdate и положение в альбоме,
+// как в выгрузке
+type ndjsonSong struct {
+	models.SongUpdate
+	TrackNumber *int `json:"trackNumber"`
+	DiscNumber  *int `json:"discNumber"`
+}
+
+// decodeNDJSON читает по одному JSON объекту в строке в формате ndjsonSong,
+// пустые строки пропускаются
+func decodeNDJSON(r io.Reader, fn func(Row) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), constants.ImportMaxLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := Row{Line: line}
+		var input ndjsonSong
+		if err := json.Unmarshal([]byte(text), &input); err != nil {
+			row.Err = i18n.Errorf(constants.ErrImportRow, err)
+		} else {
+			row.Song = models.Song{
+				Title:       strings.TrimSpace(input.Title),
+				Artist:      strings.TrimSpace(input.Artist),
+				Album:       input.Album,
+				Genre:       input.Genre,
+				Duration:    input.Duration,
+				ReleaseDate: input.ReleaseDate,
+				Text:        input.Text,
+				Link:        input.Link,
+				TrackNumber: input.TrackNumber,
+				DiscNumber:  input.DiscNumber,
+			}
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"song-library/internal/constants"
+	"song-library/internal/exporter"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+)
+
+// decodeAll читает CSV целиком и возвращает все строки
+func decodeAll(t *testing.T, input string) ([]Row, error) {
+	t.Helper()
+	var rows []Row
+	err := decodeCSV(strings.NewReader(input), func(row Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	return rows, err
+}
+
+func TestDecodeCSV(t *testing.T) {
+	rows, err := decodeAll(t, constants.UTF8BOM+"title,group,duration\nSupermassive Black Hole,Muse,209\n")
+	if err != nil {
+		t.Fatalf("decodeCSV() error = %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("rows = %+v, want 1", rows)
+	}
+	row := rows[0]
+	if row.Err != nil || row.Line != 2 {
+		t.Errorf("row = %+v", row)
+	}
+	if row.Song.Title != "Supermassive Black Hole" || row.Song.Artist != "Muse" || row.Song.Duration != 209 {
+		t.Errorf("song = %+v", row.Song)
+	}
+}
+
+// Некорректная строка попадает в отчет, остальные строки читаются дальше
+func TestDecodeCSVRowErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		row  string
+		key  string
+	}{
+		{"текст после кавычки", `"a"x,b`, constants.ErrImportRow},
+		{"кавычка внутри поля", `a"b,c`, constants.ErrImportRow},
+		{"лишнее поле", "a,b,c", constants.ErrImportFieldCount},
+		{"недостающее поле", "a", constants.ErrImportFieldCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := decodeAll(t, "title,artist\n"+tt.row+"\nSong,Band\n")
+			if err != nil {
+				t.Fatalf("decodeCSV() error = %v", err)
+			}
+			if len(rows) != 2 {
+				t.Fatalf("rows = %+v, want 2", rows)
+			}
+			if rows[0].Line != 2 || rows[1].Line != 3 {
+				t.Errorf("lines = %d, %d, want 2, 3", rows[0].Line, rows[1].Line)
+			}
+			var rowErr *i18n.Error
+			if !errors.As(rows[0].Err, &rowErr) || rowErr.Key != tt.key {
+				t.Errorf("rows[0].Err = %v, want key %q", rows[0].Err, tt.key)
+			}
+			if rows[1].Err != nil || rows[1].Song.Title != "Song" {
+				t.Errorf("rows[1] = %+v", rows[1])
+			}
+		})
+	}
+}
+
+func TestDecodeCSVInvalidDuration(t *testing.T) {
+	rows, err := decodeAll(t, "title,artist,duration\nSong,Band,x\n")
+	if err != nil {
+		t.Fatalf("decodeCSV() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Err == nil || rows[0].Err.Error() != constants.ErrDurationRequired {
+		t.Errorf("rows = %+v", rows)
+	}
+}
+
+func TestDecodeCSVUnterminatedQuote(t *testing.T) {
+	rows, err := decodeAll(t, "title,artist\nSong,Band\n\"Open,Band\nNext,Band\n")
+	if err != nil {
+		t.Fatalf("decodeCSV() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].Err != nil {
+		t.Fatalf("rows = %+v, want valid row and error", rows)
+	}
+	if rows[1].Err == nil || rows[1].Line != 3 {
+		t.Errorf("rows[1] = %+v, want error at line 3", rows[1])
+	}
+}
+
+func TestDecodeCSVMissingColumn(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		column string
+	}{
+		{"нет title", "artist,album", constants.ColumnTitle},
+		{"нет artist и group", "title,album", constants.ColumnArtist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := decodeAll(t, tt.header+"\nSong,Album\n")
+			var headerErr *i18n.Error
+			if !errors.As(err, &headerErr) || headerErr.Key != constants.ErrImportMissingColumn {
+				t.Fatalf("decodeCSV() error = %v, want missing column", err)
+			}
+			if len(headerErr.Args) != 1 || headerErr.Args[0] != tt.column {
+				t.Errorf("args = %v, want %q", headerErr.Args, tt.column)
+			}
+			if len(rows) != 0 {
+				t.Errorf("rows = %+v, want none", rows)
+			}
+		})
+	}
+}
+
+// Выгрузка читается импортом без потери полей песни
+func TestDecodeExportRoundTrip(t *testing.T) {
+	track, disc := 3, 1
+	song := models.Song{
+		ID: 7, Title: "Supermassive Black Hole", Artist: "Muse", Album: "Black Holes and Revelations",
+		Genre: "Rock", Duration: 209, ReleaseDate: "2006-07-16", Text: "Ooh baby, don't you know I suffer?",
+		Link: "https://example.com", TrackNumber: &track, DiscNumber: &disc,
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	want := models.Song{
+		Title: song.Title, Artist: song.Artist, Album: song.Album, Genre: song.Genre,
+		Duration: song.Duration, ReleaseDate: song.ReleaseDate, Text: song.Text, Link: song.Link,
+		TrackNumber: &track, DiscNumber: &disc,
+	}
+
+	for _, format := range []string{constants.ImportFormatCSV, constants.ImportFormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			encoder, err := exporter.NewEncoder(format, &buf, false)
+			if err != nil {
+				t.Fatalf("NewEncoder() error = %v", err)
+			}
+			if err := encoder.Write(models.SongExport{Song: song}); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if err := encoder.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			decode, err := decoderFor(format)
+			if err != nil {
+				t.Fatalf("decoderFor() error = %v", err)
+			}
+			var rows []Row
+			if err := decode(&buf, func(row Row) error {
+				rows = append(rows, row)
+				return nil
+			}); err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+			if len(rows) != 1 || rows[0].Err != nil {
+				t.Fatalf("rows = %+v", rows)
+			}
+			if !reflect.DeepEqual(rows[0].Song, want) {
+				t.Errorf("song = %+v, want %+v", rows[0].Song, want)
+			}
+		})
+	}
+}
+
+func TestDecodeCSVInvalidTrackNumber(t *testing.T) {
+	rows, err := decodeAll(t, "title,artist,track_number\nSong,Band,first\n")
+	if err != nil {
+		t.Fatalf("decodeCSV() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Err == nil || rows[0].Err.Error() != constants.ErrInvalidTrackNumber {
+		t.Errorf("rows = %+v", rows)
+	}
+}
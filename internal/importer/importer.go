@@ -0,0 +1,226 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+	"song-library/internal/repository"
+)
+
+// ErrInvalidInput - файл нельзя разобрать: неизвестный формат, нет заголовка или обязательной колонки
+var ErrInvalidInput = errors.New(constants.ErrImportInvalidInput)
+
+// Options - параметры импорта. При DryRun строки проверяются и вставляются
+// в транзакции, которая затем откатывается
+type Options struct {
+	Format string
+	DryRun bool
+}
+
+// Importer загружает песни пакетами без обращения к внешнему сервису
+type Importer struct {
+	repo      *repository.SongRepository
+	logger    *i18n.Logger
+	batchSize int
+}
+
+func NewImporter(repo *repository.SongRepository, logger *i18n.Logger) *Importer {
+	return &Importer{repo: repo, logger: logger, batchSize: constants.ImportBatchSize}
+}
+
+// Import читает песни из r и возвращает отчет по каждой строке. Ошибка возвращается,
+// только если импорт нельзя продолжить (неизвестный формат, сбой чтения или БД).
+// Автор и ID запроса для истории изменений берутся из ctx
+func (i *Importer) Import(ctx context.Context, r io.Reader, opts Options) (*models.ImportReport, error) {
+	decode, err := decoderFor(opts.Format)
+	if err != nil {
+		return nil, i18n.Errorf(constants.ErrWrapFormat, ErrInvalidInput, err)
+	}
+
+	tx, err := i.repo.BeginImport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // после Commit ничего не откатывает
+
+	run := importRun{
+		tx:     tx,
+		report: &models.ImportReport{DryRun: opts.DryRun, Rows: []models.ImportRowResult{}},
+		seen:   make(map[string]int),
+	}
+
+	var flushErr error
+	err = decode(r, func(row Row) error {
+		run.add(row)
+		if len(run.batch) >= i.batchSize {
+			flushErr = run.flush()
+		}
+		return flushErr
+	})
+	if err != nil && flushErr == nil {
+		return nil, i18n.Errorf(constants.ErrWrapFormat, ErrInvalidInput, err)
+	}
+	if err == nil {
+		err = run.flush()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.DryRun {
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := run.report
+	// ошибки разбора попадают в отчет раньше строк своего пакета
+	slices.SortStableFunc(report.Rows, func(a, b models.ImportRowResult) int {
+		return a.Line - b.Line
+	})
+	i.logger.Printf(constants.LogImportFinished, report.Created, report.Skipped, report.Failed, report.DryRun)
+	return report, nil
+}
+
+// importRun - состояние одного импорта: накопленный пакет и строки, уже встреченные в файле
+type importRun struct {
+	tx     *repository.SongImport
+	report *models.ImportReport
+	batch  []Row
+	// seen хранит номер первой строки для каждого ключа песни, ключи вычисляются
+	// в БД при сбросе пакета так же, как normalized_key
+	seen map[string]int
+}
+
+func (run *importRun) add(row Row) {
+	if row.Err == nil {
+		row.Err = validate(row.Song)
+	}
+	if row.Err != nil {
+		run.result(row, constants.ImportStatusFailed, 0, row.Err.Error())
+		return
+	}
+
+	run.batch = append(run.batch, row)
+}
+
+// flush пропускает песни, уже имеющиеся в библиотеке или встреченные в файле раньше,
+// и вставляет остальные одним пакетом
+func (run *importRun) flush() error {
+	if len(run.batch) == 0 {
+		return nil
+	}
+	batch := run.batch
+	run.batch = nil
+
+	songs := make([]models.Song, len(batch))
+	for n, row := range batch {
+		songs[n] = row.Song
+	}
+	keys, existing, err := run.tx.Keys(songs)
+	if err != nil {
+		return err
+	}
+
+	var rows []Row
+	var rowKeys []string
+	for n, row := range batch {
+		key := keys[n]
+		if existing[key] {
+			run.result(row, constants.ImportStatusSkipped, 0, constants.ErrImportDuplicate)
+			continue
+		}
+		if line, ok := run.seen[key]; ok {
+			run.result(row, constants.ImportStatusSkipped, 0, fmt.Sprintf(constants.ErrImportDuplicateRow, line))
+			continue
+		}
+		run.seen[key] = row.Line
+		rows = append(rows, row)
+		rowKeys = append(rowKeys, key)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	err = run.insert(rows, rowKeys)
+	if err == nil {
+		return nil
+	}
+	if len(rows) == 1 {
+		run.result(rows[0], constants.ImportStatusFailed, 0, fmt.Sprintf(constants.ErrImportBatch, err))
+		return nil
+	}
+	// пакет откатан до точки сохранения, повторяем построчно, чтобы найти ошибочные строки
+	for n, row := range rows {
+		if err := run.insert([]Row{row}, rowKeys[n:n+1]); err != nil {
+			run.result(row, constants.ImportStatusFailed, 0, fmt.Sprintf(constants.ErrImportBatch, err))
+		}
+	}
+	return nil
+}
+
+// insert вставляет rows с ключами keys одним запросом и отмечает их созданными
+func (run *importRun) insert(rows []Row, keys []string) error {
+	songs := make([]models.Song, len(rows))
+	for n, row := range rows {
+		songs[n] = row.Song
+	}
+	ids, err := run.tx.Insert(songs, keys)
+	if err != nil {
+		return err
+	}
+
+	for n, row := range rows {
+		id := ids[n]
+		if run.report.DryRun {
+			id = 0
+		}
+		run.result(row, constants.ImportStatusCreated, id, "")
+	}
+	return nil
+}
+
+func (run *importRun) result(row Row, status string, id int, reason string) {
+	switch status {
+	case constants.ImportStatusCreated:
+		run.report.Created++
+	case constants.ImportStatusSkipped:
+		run.report.Skipped++
+	case constants.ImportStatusFailed:
+		run.report.Failed++
+	}
+	run.report.Rows = append(run.report.Rows, models.ImportRowResult{
+		Line:   row.Line,
+		Status: status,
+		ID:     id,
+		Title:  row.Song.Title,
+		Artist: row.Song.Artist,
+		Reason: reason,
+	})
+}
+
+// validate повторяет проверки PUT /api/songs/{id}
+func validate(s models.Song) error {
+	if s.Title == "" || s.Artist == "" {
+		return errors.New(constants.ErrRequiredFields)
+	}
+	if s.Duration < 0 {
+		return errors.New(constants.ErrDurationRequired)
+	}
+	if (s.TrackNumber != nil && *s.TrackNumber < 1) || (s.DiscNumber != nil && *s.DiscNumber < 1) {
+		return errors.New(constants.ErrInvalidTrackNumber)
+	}
+	if s.ReleaseDate != "" {
+		if _, err := time.Parse(constants.DateFormat, s.ReleaseDate); err != nil {
+			return errors.New(constants.ErrInvalidReleaseDate)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package lyrics
+
+import (
+	"strings"
+
+	"song-library/internal/constants"
+)
+
+// LineChange - строка построчного сравнения текстов. Op - одна из операций
+// constants.DiffOpEqual, DiffOpInsert или DiffOpDelete
+type LineChange struct {
+	Op   string
+	Text string
+}
+
+// DiffLines сравнивает тексты построчно по наибольшей общей подпоследовательности
+// и возвращает строки обоих текстов в порядке следования, удаленные перед добавленными
+func DiffLines(before, after string) []LineChange {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// common[i][j] - длина общей подпоследовательности a[i:] и b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	changes := make([]LineChange, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			changes = append(changes, LineChange{Op: constants.DiffOpEqual, Text: a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			changes = append(changes, LineChange{Op: constants.DiffOpDelete, Text: a[i]})
+			i++
+		default:
+			changes = append(changes, LineChange{Op: constants.DiffOpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		changes = append(changes, LineChange{Op: constants.DiffOpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		changes = append(changes, LineChange{Op: constants.DiffOpInsert, Text: b[j]})
+	}
+	return changes
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
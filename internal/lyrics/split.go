@@ -0,0 +1,89 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"song-library/internal/constants"
+)
+
+// blankLines разделяет блоки текста: одна или несколько пустых строк
+var blankLines = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// Block - часть текста песни между пустыми строками
+type Block struct {
+	Type    string
+	Content string
+	// StartMs и EndMs - время куплета от начала песни, nil если не задано
+	StartMs *int
+	EndMs   *int
+}
+
+// Splitter разбивает текст песни на куплеты.
+// При DetectChorus повторяющиеся блоки помечаются как припев
+type Splitter struct {
+	DetectChorus bool
+}
+
+func (s Splitter) Split(text string) []Block {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	var blocks []Block
+	for _, part := range blankLines.Split(text, -1) {
+		content := trimLines(part)
+		if content == "" {
+			continue
+		}
+		blocks = append(blocks, Block{Type: constants.DefaultVerseType, Content: content})
+	}
+
+	if s.DetectChorus {
+		markRepeated(blocks)
+	}
+	return blocks
+}
+
+// markRepeated помечает как припев блоки, встречающиеся в тексте более одного раза
+func markRepeated(blocks []Block) {
+	counts := make(map[string]int, len(blocks))
+	for _, b := range blocks {
+		counts[normalize(b.Content)]++
+	}
+	for i, b := range blocks {
+		if counts[normalize(b.Content)] > 1 {
+			blocks[i].Type = constants.ChorusVerseType
+		}
+	}
+}
+
+// normalize убирает регистр, пунктуацию и лишние пробелы для сравнения блоков
+func normalize(content string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(content) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			space = true
+		}
+	}
+	return b.String()
+}
+
+func trimLines(block string) string {
+	lines := strings.Split(block, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
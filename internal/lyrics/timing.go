@@ -0,0 +1,227 @@
+package lyrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+)
+
+var (
+	// ErrInvalidTimings - временные метки куплетов не по порядку или за пределами песни
+	ErrInvalidTimings = errors.New(constants.ErrInvalidTimings)
+	// ErrInvalidLRC - файл LRC не содержит строк с временными метками
+	ErrInvalidLRC = errors.New(constants.ErrInvalidLRC)
+	// ErrNoTimings - у куплетов нет меток, нужных для формата вывода
+	ErrNoTimings = errors.New(constants.ErrNoTimings)
+)
+
+// lrcTime - метка [мм:сс], [мм:сс.xx] или [мм:сс.xxx] в начале строки LRC
+var lrcTime = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+
+// lrcOffset - тег [offset:+/-мс], сдвигающий все метки файла
+var lrcOffset = regexp.MustCompile(`^\[offset:\s*([+-]?\d+)\s*\]$`)
+
+type lrcLine struct {
+	ms   int
+	text string
+}
+
+// ParseLRC разбирает файл LRC: каждая строка с меткой становится куплетом,
+// окончанием куплета считается метка следующей строки. Строки без текста только
+// завершают предыдущую строку, теги метаданных (кроме offset) пропускаются
+func ParseLRC(r io.Reader) ([]Block, error) {
+	scanner := bufio.NewScanner(r)
+	offset := 0
+	var lines []lrcLine
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), constants.UTF8BOM))
+		if m := lrcOffset.FindStringSubmatch(line); m != nil {
+			offset, _ = strconv.Atoi(m[1])
+			continue
+		}
+
+		// у строки может быть несколько меток, если она повторяется (припев)
+		var times []int
+		for m := lrcTime.FindStringSubmatch(line); m != nil; m = lrcTime.FindStringSubmatch(line) {
+			times = append(times, lrcMillis(m[1], m[2], m[3]))
+			line = line[len(m[0]):]
+		}
+		text := strings.TrimSpace(line)
+		for _, ms := range times {
+			lines = append(lines, lrcLine{ms: ms, text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// положительный offset означает, что текст должен появляться раньше
+	for i := range lines {
+		lines[i].ms = max(lines[i].ms-offset, 0)
+	}
+	slices.SortStableFunc(lines, func(a, b lrcLine) int { return a.ms - b.ms })
+
+	var blocks []Block
+	for i, line := range lines {
+		if line.text == "" {
+			continue
+		}
+		block := Block{Type: constants.DefaultVerseType, Content: line.text, StartMs: intPtr(line.ms)}
+		if i+1 < len(lines) && lines[i+1].ms > line.ms {
+			block.EndMs = intPtr(lines[i+1].ms)
+		}
+		blocks = append(blocks, block)
+	}
+	if len(blocks) == 0 {
+		return nil, ErrInvalidLRC
+	}
+	return blocks, nil
+}
+
+// lrcMillis переводит минуты, секунды и дробную часть метки в миллисекунды.
+// Дробная часть из двух цифр - сотые доли секунды, из одной - десятые
+func lrcMillis(minutes, seconds, fraction string) int {
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(seconds)
+	ms := (m*60 + s) * 1000
+	if fraction != "" {
+		f, _ := strconv.Atoi(fraction)
+		for n := len(fraction); n < 3; n++ {
+			f *= 10
+		}
+		ms += f
+	}
+	return ms
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// ValidateTimings проверяет, что метки куплетов идут по порядку без перекрытий,
+// окончание позже начала и не выходит за durationMs. Куплеты без меток пропускаются,
+// durationMs = 0 означает, что длительность песни неизвестна
+func ValidateTimings(blocks []Block, durationMs int) error {
+	previous := 0
+	for i, b := range blocks {
+		number := i + 1
+		if b.StartMs != nil {
+			if *b.StartMs < 0 {
+				return invalidTimings(constants.ErrTimingNegative, number)
+			}
+			if *b.StartMs < previous {
+				return invalidTimings(constants.ErrTimingOrder, number)
+			}
+			previous = *b.StartMs
+		}
+		if b.EndMs != nil {
+			if *b.EndMs <= previous {
+				return invalidTimings(constants.ErrTimingEnd, number)
+			}
+			previous = *b.EndMs
+		}
+		if durationMs > 0 && previous > durationMs {
+			return invalidTimings(constants.ErrTimingDuration, number)
+		}
+	}
+	return nil
+}
+
+func invalidTimings(format string, number int) error {
+	return i18n.Errorf(constants.ErrWrapFormat, ErrInvalidTimings, fmt.Sprintf(format, number))
+}
+
+// Render выводит куплеты в формате lrc, srt или txt. Для lrc и srt у каждого
+// куплета должно быть время начала. Окончание субтитра srt без end_ms берется из
+// начала следующего куплета, для последнего - из длительности песни durationMs
+func Render(w io.Writer, format string, blocks []Block, durationMs int) error {
+	switch format {
+	case constants.LyricsFormatTXT:
+		return renderText(w, blocks)
+	case constants.LyricsFormatLRC:
+		return renderLRC(w, blocks)
+	case constants.LyricsFormatSRT:
+		return renderSRT(w, blocks, durationMs)
+	default:
+		return i18n.Errorf(constants.ErrUnknownLyricsFormat, format)
+	}
+}
+
+func renderText(w io.Writer, blocks []Block) error {
+	contents := make([]string, len(blocks))
+	for i, b := range blocks {
+		contents[i] = b.Content
+	}
+	_, err := fmt.Fprintln(w, strings.Join(contents, constants.VerseSeparator))
+	return err
+}
+
+// renderLRC выводит каждую строку куплета с меткой его начала. Если после куплета
+// пауза, окончание выводится отдельной пустой строкой с меткой
+func renderLRC(w io.Writer, blocks []Block) error {
+	if err := requireStart(blocks); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for i, block := range blocks {
+		for _, line := range strings.Split(block.Content, "\n") {
+			fmt.Fprintf(&b, constants.LRCLineFormat, lrcStamp(*block.StartMs), line)
+		}
+		if block.EndMs != nil && (i+1 == len(blocks) || *blocks[i+1].StartMs != *block.EndMs) {
+			fmt.Fprintf(&b, constants.LRCLineFormat, lrcStamp(*block.EndMs), "")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func renderSRT(w io.Writer, blocks []Block, durationMs int) error {
+	if err := requireStart(blocks); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for i, block := range blocks {
+		end := durationMs
+		switch {
+		case block.EndMs != nil:
+			end = *block.EndMs
+		case i+1 < len(blocks):
+			end = *blocks[i+1].StartMs
+		}
+		if end <= *block.StartMs {
+			return i18n.Errorf(constants.ErrWrapFormat, ErrNoTimings, fmt.Sprintf(constants.ErrNoEndTime, i+1))
+		}
+		fmt.Fprintf(&b, constants.SRTCueFormat, i+1, srtStamp(*block.StartMs), srtStamp(end), block.Content)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func requireStart(blocks []Block) error {
+	for i, b := range blocks {
+		if b.StartMs == nil {
+			return i18n.Errorf(constants.ErrWrapFormat, ErrNoTimings, fmt.Sprintf(constants.ErrNoStartTime, i+1))
+		}
+	}
+	return nil
+}
+
+// lrcStamp форматирует метку LRC мм:сс.xx
+func lrcStamp(ms int) string {
+	return fmt.Sprintf(constants.LRCTimeFormat, ms/60000, ms/1000%60, ms%1000/10)
+}
+
+// srtStamp форматирует метку SRT чч:мм:сс,ммм
+func srtStamp(ms int) string {
+	return fmt.Sprintf(constants.SRTTimeFormat, ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -19,4 +19,15 @@ var (
 			constants.MetricLabelStatus,
 		},
 	)
+
+	RateLimitedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: constants.MetricRateLimitedTotal,
+			Help: constants.MetricRateLimitedHelp,
+		},
+		[]string{
+			constants.MetricLabelEndpoint,
+			constants.MetricLabelClient,
+		},
+	)
 )
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"song-library/internal/audit"
+	"song-library/internal/constants"
+)
+
+// RequestID берет ID запроса из заголовка X-Request-ID или формирует новый,
+// возвращает его в ответе и передает в контексте запроса
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(constants.HeaderRequestID)
+		if !validRequestID(id) {
+			id = audit.NewRequestID()
+		}
+		w.Header().Set(constants.HeaderRequestID, id)
+		next.ServeHTTP(w, r.WithContext(audit.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID принимает только короткие ID из букв, цифр, '-', '_' и '.',
+// чтобы клиент не мог подставить в логи произвольный текст
+func validRequestID(id string) bool {
+	if id == "" || len(id) > constants.RequestIDMaxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"song-library/internal/audit"
+	"song-library/internal/auth"
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/problem"
+)
+
+// Auth проверяет учетные данные запросов к API. Запросы на изменение требуют
+// API-ключ или JWT, GET и HEAD без учетных данных пропускаются, если publicRead.
+// Переданные, но не принятые учетные данные отклоняются для любого метода.
+// Аутентифицированный клиент передается в контексте и записывается автором изменений
+func Auth(authenticator *auth.Authenticator, publicRead bool, logger *i18n.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, constants.APIBasePath+"/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r)
+			if errors.Is(err, auth.ErrInvalidCredentials) {
+				logger.Printf(constants.LogAuthFailed, r.Method, r.URL.Path, err)
+				unauthorized(w, constants.CodeInvalidCredentials, constants.ErrInvalidCredentials)
+				return
+			}
+			if err != nil {
+				logger.Printf(constants.LogError, constants.ErrAuthenticating, err)
+				problem.Internal(w, constants.ErrAuthenticating)
+				return
+			}
+
+			if principal == nil {
+				if publicRead && isReadMethod(r.Method) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				unauthorized(w, constants.CodeUnauthorized, constants.ErrUnauthorized)
+				return
+			}
+
+			if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+				info.principal = principal.Name
+			}
+			ctx := auth.WithPrincipal(r.Context(), principal)
+			ctx = audit.WithActor(ctx, principal.Name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func unauthorized(w http.ResponseWriter, code, message string) {
+	w.Header().Set(constants.HeaderWWWAuthenticate, constants.AuthChallenge)
+	problem.Error(w, code, message, http.StatusUnauthorized)
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"song-library/internal/auth"
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/problem"
+)
+
+// Authorize пропускает запрос, если роль клиента не ниже required. Анонимный запрос,
+// пропущенный Auth, допускается только к маршрутам роли viewer, для остальных - 401.
+// При недостаточной роли отвечает 403 с описанием требуемой роли
+func Authorize(required string, logger *i18n.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := auth.FromContext(r.Context())
+			if principal == nil {
+				if required == constants.RoleViewer {
+					next.ServeHTTP(w, r)
+					return
+				}
+				unauthorized(w, constants.CodeUnauthorized, constants.ErrUnauthorized)
+				return
+			}
+
+			if !principal.HasRole(required) {
+				logger.Printf(constants.LogAccessDenied, principal.Name, principal.Role, r.Method, r.URL.Path, required)
+				problem.Forbidden(w, principal.Role, required)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"song-library/internal/constants"
+)
+
+// Deprecated помечает устаревший маршрут заголовками Deprecation и Link
+// с указанием маршрута, который следует использовать вместо него
+func Deprecated(successor string) func(http.Handler) http.Handler {
+	link := fmt.Sprintf(constants.SuccessorLinkFormat, successor)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(constants.HeaderDeprecation, constants.DeprecationValue)
+			w.Header().Set(constants.HeaderLink, link)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+)
+
+// Language выбирает язык сообщений ответа по заголовку Accept-Language и
+// указывает его в Content-Language, откуда его берет пакет problem
+func Language(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.Match(r.Header.Get(constants.HeaderAcceptLanguage))
+		w.Header().Set(constants.HeaderContentLanguage, lang.String())
+		w.Header().Add(constants.HeaderVary, constants.HeaderAcceptLanguage)
+		next.ServeHTTP(w, r)
+	})
+}
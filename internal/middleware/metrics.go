@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"song-library/internal/constants"
 	"song-library/internal/metrics"
 )
 
@@ -14,10 +15,16 @@ func MetricsMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(rw, r)
 
+		// Используем шаблон маршрута, чтобы ID в пути не раздували число меток
+		endpoint := r.Pattern
+		if endpoint == "" {
+			endpoint = constants.MetricEndpointUnmatched
+		}
+
 		// Увеличиваем счетчик метрик
 		metrics.HttpRequestsTotal.WithLabelValues(
 			r.Method,
-			r.URL.Path,
+			endpoint,
 			strconv.Itoa(rw.status),
 		).Inc()
 	})
@@ -32,3 +39,7 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.status = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -1,11 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/rs/zerolog"
 
+	"song-library/internal/audit"
 	"song-library/internal/constants"
 )
 
@@ -19,19 +21,35 @@ func (w *statusWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 }
 
+// Unwrap позволяет http.ResponseController добраться до исходного соединения
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// requestInfo собирает сведения о запросе, которые становятся известны во вложенных
+// middleware, например аутентифицированного клиента
+type requestInfo struct {
+	principal string
+}
+
+type requestInfoKey struct{}
+
 func RequestLogger(log zerolog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
 			sw := &statusWriter{ResponseWriter: w}
-			next.ServeHTTP(sw, r)
+			info := &requestInfo{}
+			next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), requestInfoKey{}, info)))
 
 			log.Info().
 				Str(constants.LogFieldMethod, r.Method).
 				Str(constants.LogFieldPath, r.URL.Path).
 				Int(constants.LogFieldStatus, sw.status).
 				Dur(constants.LogFieldDuration, time.Since(start)).
+				Str(constants.LogFieldRequestID, audit.RequestID(r.Context())).
+				Str(constants.LogFieldPrincipal, info.principal).
 				Msg(constants.LogMsgRequest)
 		})
 	}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"time"
+
+	"song-library/internal/auth"
+	"song-library/internal/config"
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/metrics"
+	"song-library/internal/problem"
+	"song-library/internal/ratelimit"
+)
+
+// RateLimits хранит ограничители частоты запросов: общий по IP клиента,
+// собственные для маршрутов с отдельным лимитом и общий для остальных маршрутов
+type RateLimits struct {
+	ip             *ratelimit.Limiter
+	fallback       *ratelimit.Limiter
+	routes         map[string]*ratelimit.Limiter
+	registered     map[string]bool
+	trustedProxies []netip.Prefix
+	logger         *i18n.Logger
+}
+
+// NewRateLimits создает ограничители по настройкам cfg
+func NewRateLimits(cfg config.RateLimitConfig, logger *i18n.Logger) *RateLimits {
+	limits := &RateLimits{
+		routes:         make(map[string]*ratelimit.Limiter),
+		registered:     make(map[string]bool),
+		trustedProxies: cfg.TrustedProxies,
+		logger:         logger,
+	}
+	if cfg.IP.Rate > 0 {
+		limits.ip = ratelimit.New(cfg.IP.Rate, cfg.IP.Burst)
+	}
+	if cfg.Default.Rate > 0 {
+		limits.fallback = ratelimit.New(cfg.Default.Rate, cfg.Default.Burst)
+	}
+	for pattern, limit := range cfg.Routes {
+		if limit.Rate > 0 {
+			limits.routes[pattern] = ratelimit.New(limit.Rate, limit.Burst)
+		} else {
+			limits.routes[pattern] = nil
+		}
+	}
+	return limits
+}
+
+// For возвращает middleware ограничения частоты для маршрута method path. Клиент
+// определяется по аутентифицированному клиенту, а без него - по IP-адресу.
+// Ответ содержит заголовки RateLimit-*, при превышении лимита - 429 и Retry-After
+func (l *RateLimits) For(method, path string) func(http.Handler) http.Handler {
+	pattern := fmt.Sprintf(constants.RouteFormat, method, path)
+	l.registered[pattern] = true
+	limiter, ok := l.routes[pattern]
+	if !ok {
+		limiter = l.fallback
+	}
+
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, client := l.clientKey(r)
+			result := limiter.Allow(key)
+
+			setRateLimitHeaders(w, result)
+			if !result.Allowed {
+				l.reject(w, r, result, key, r.Pattern, client)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ByIP ограничивает частоту всех запросов по IP клиента. Подключается до
+// аутентификации и маршрутизации, чтобы ограничивать и подбор учетных данных,
+// и запросы к несуществующим маршрутам. Заголовки RateLimit-* здесь выставляются
+// только при отказе: у разрешенного запроса их выставит лимит маршрута
+func (l *RateLimits) ByIP(next http.Handler) http.Handler {
+	if l.ip == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := constants.RateLimitIPPrefix + ratelimit.ClientIP(r, l.trustedProxies)
+		if result := l.ip.Allow(key); !result.Allowed {
+			setRateLimitHeaders(w, result)
+			l.reject(w, r, result, key, constants.MetricEndpointUnmatched, constants.RateLimitClientIP)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CheckRoutes возвращает ошибку, если в настройках задан лимит для маршрута,
+// который не был зарегистрирован через For. Вызывается после сборки маршрутов
+func (l *RateLimits) CheckRoutes() error {
+	patterns := slices.Sorted(maps.Keys(l.routes))
+	for _, pattern := range patterns {
+		if !l.registered[pattern] {
+			return i18n.Errorf(constants.ErrUnknownLimitRoute, pattern)
+		}
+	}
+	return nil
+}
+
+// reject отвечает 429 и учитывает отказ в метриках с меткой маршрута endpoint
+func (l *RateLimits) reject(w http.ResponseWriter, r *http.Request, result ratelimit.Result, key, endpoint, client string) {
+	metrics.RateLimitedTotal.WithLabelValues(endpoint, client).Inc()
+	l.logger.Printf(constants.LogRateLimited, key, r.Method, r.URL.Path)
+	w.Header().Set(constants.HeaderRetryAfter, seconds(result.RetryAfter))
+	problem.Error(w, constants.CodeRateLimited, constants.ErrTooManyRequests, http.StatusTooManyRequests)
+}
+
+func setRateLimitHeaders(w http.ResponseWriter, result ratelimit.Result) {
+	w.Header().Set(constants.HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+	w.Header().Set(constants.HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+	w.Header().Set(constants.HeaderRateLimitReset, seconds(result.Reset))
+}
+
+// clientKey возвращает ключ корзины клиента и тип клиента для метрик
+func (l *RateLimits) clientKey(r *http.Request) (string, string) {
+	if principal := auth.FromContext(r.Context()); principal != nil {
+		return constants.RateLimitKeyPrefix + principal.Name, constants.RateLimitClientKey
+	}
+	return constants.RateLimitIPPrefix + ratelimit.ClientIP(r, l.trustedProxies), constants.RateLimitClientIP
+}
+
+// seconds округляет длительность вверх до целых секунд для заголовков
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,6 +12,7 @@ import (
 
 	"song-library/internal/config"
 	"song-library/internal/constants"
+	"song-library/internal/i18n"
 	"song-library/internal/utils"
 
 	"golang.org/x/text/cases"
@@ -31,16 +31,11 @@ const (
 	sqlGetAppliedMigrations  = "get_applied_migrations.sql"
 	sqlInsertMigration       = "insert_migration.sql"
 	sqlDeleteMigration       = "delete_migration.sql"
-
-	actionApply    = "применения"
-	actionRollback = "отката"
-	resultApplied  = "применены"
-	resultRolled   = "отменены"
 )
 
 type Migrator struct {
 	db     *sql.DB
-	logger *log.Logger
+	logger *i18n.Logger
 }
 
 type Migration struct {
@@ -49,9 +44,9 @@ type Migration struct {
 	Content  []byte
 }
 
-func NewMigrator(ctx context.Context, dbConfig config.DatabaseConfig, logger *log.Logger) (*Migrator, error) {
+func NewMigrator(ctx context.Context, dbConfig config.DatabaseConfig, logger *i18n.Logger) (*Migrator, error) {
 	if logger == nil {
-		return nil, fmt.Errorf(constants.ErrLoggerNil)
+		return nil, i18n.Errorf(constants.ErrLoggerNil)
 	}
 
 	logger.Printf(constants.LogDBConnecting, dbConfig.Host, dbConfig.Port)
@@ -66,12 +61,12 @@ func NewMigrator(ctx context.Context, dbConfig config.DatabaseConfig, logger *lo
 		dbConfig.SSLMode,
 	))
 	if err != nil {
-		logger.Printf(constants.LogError, constants.ErrDBConnection, err.Error())
-		return nil, fmt.Errorf(constants.ErrDBConnection, err)
+		logger.Printf(constants.LogError, constants.ErrDBConnection, err)
+		return nil, i18n.Errorf(constants.ErrDBConnection, err)
 	}
 
 	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf(constants.ErrDBConnection, err)
+		return nil, i18n.Errorf(constants.ErrDBConnection, err)
 	}
 
 	logger.Println(constants.LogDBConnected)
@@ -88,7 +83,7 @@ func (m *Migrator) getMigrationFiles(suffixWord string) ([]string, error) {
 	migrationsDir := filepath.Join(projectRoot, migrationsPath)
 	files, err := os.ReadDir(migrationsDir)
 	if err != nil {
-		return nil, fmt.Errorf(constants.ErrReadingMigrationDir, err)
+		return nil, i18n.Errorf(constants.ErrReadingMigrationDir, err)
 	}
 
 	suffix := fmt.Sprintf(constants.SQLSuffix, suffixWord)
@@ -109,29 +104,29 @@ func (m *Migrator) getMigrationFiles(suffixWord string) ([]string, error) {
 // direction - направление миграции ("up" или "down")
 func (m *Migrator) executeMigrations(ctx context.Context, direction string) error {
 	if ctx == nil {
-		return fmt.Errorf(constants.ErrContextNil)
+		return i18n.Errorf(constants.ErrContextNil)
 	}
 
 	startTime := time.Now()
 
 	actionNames := map[string]string{
-		directionUp:   actionApply,
-		directionDown: actionRollback,
+		directionUp:   constants.LogMigrationApply,
+		directionDown: constants.LogMigrationRollbackAction,
 	}
 	actionResults := map[string]string{
-		directionUp:   resultApplied,
-		directionDown: resultRolled,
+		directionUp:   constants.LogMigrationApplied,
+		directionDown: constants.LogMigrationRolledBack,
 	}
 
 	actionName, ok := actionNames[direction]
 	if !ok {
-		return fmt.Errorf(constants.ErrMigrationDiraction, direction)
+		return i18n.Errorf(constants.ErrMigrationDiraction, direction)
 	}
 
 	m.logger.Printf(constants.LogMigrationStart, actionName)
 
 	if err := m.ensureMigrationsTable(ctx); err != nil {
-		return fmt.Errorf(constants.ErrMigrationTableCheck, err)
+		return i18n.Errorf(constants.ErrMigrationTableCheck, err)
 	}
 
 	files, err := m.getMigrationFiles(direction)
@@ -182,7 +177,7 @@ func (m *Migrator) executeMigrations(ctx context.Context, direction string) erro
 			return err
 		}
 
-		m.logger.Printf(constants.LogMigrationProcess, cases.Title(language.Russian).String(actionName), file)
+		m.logger.Printf(constants.LogMigrationProcess, cases.Title(language.Russian).String(m.logger.Translate(actionName)), file)
 	}
 
 	duration := time.Since(startTime)
@@ -214,7 +209,7 @@ func (m *Migrator) ensureMigrationsTable(ctx context.Context) error {
 func (m *Migrator) executeInTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
 	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf(constants.ErrTransactionStart, err)
+		return i18n.Errorf(constants.ErrTransactionStart, err)
 	}
 	defer tx.Rollback() // откатится только если не было commit
 
@@ -223,7 +218,7 @@ func (m *Migrator) executeInTransaction(ctx context.Context, fn func(*sql.Tx) er
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf(constants.ErrTransactionCommit, err)
+		return i18n.Errorf(constants.ErrTransactionCommit, err)
 	}
 	return nil
 }
@@ -277,12 +272,12 @@ func extractVersionFromFileName(filename string) string {
 func (m *Migrator) executeSingleMigration(ctx context.Context, tx *sql.Tx, file string) error {
 	content, err := os.ReadFile(file)
 	if err != nil {
-		return fmt.Errorf(constants.ErrReadingMigrationFile, file, err)
+		return i18n.Errorf(constants.ErrReadingMigrationFile, file, err)
 	}
 
 	_, err = tx.ExecContext(ctx, string(content))
 	if err != nil {
-		return fmt.Errorf(constants.ErrExecutingMigration, file, err)
+		return i18n.Errorf(constants.ErrExecutingMigration, file, err)
 	}
 
 	return nil
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// APIKey - API-ключ без секрета: Prefix позволяет узнать ключ, не раскрывая его
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       string     `json:"role"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Artist - исполнитель. SongCount учитывает и участие в песнях в качестве приглашенного
+type Artist struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	SongCount int       `json:"song_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Album - альбом основного исполнителя. Год и обложка задаются один раз для всех песен
+type Album struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	ArtistID    int       `json:"artist_id"`
+	ArtistName  string    `json:"artist_name"`
+	ReleaseYear *int      `json:"release_year,omitempty"`
+	CoverURL    string    `json:"cover_url,omitempty"`
+	SongCount   int       `json:"song_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AlbumUpdate - метаданные альбома, название и исполнитель берутся из песен
+type AlbumUpdate struct {
+	ReleaseYear *int   `json:"release_year"`
+	CoverURL    string `json:"cover_url"`
+}
+
+type Genre struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	SongCount int    `json:"song_count"`
+}
+
+// SongArtist - участие исполнителя в песне: main или featuring
+type SongArtist struct {
+	ArtistID int    `json:"artist_id"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Position int    `json:"position"`
+}
+
+// SongFeaturing - приглашенные исполнители песни в порядке упоминания.
+// Основной исполнитель задается полем artist песни
+type SongFeaturing struct {
+	Featuring []string `json:"featuring"`
+}
+
+// CatalogFilter - поиск по названию и пагинация справочников
+type CatalogFilter struct {
+	Query    string
+	ArtistID int
+	Page     int
+	PerPage  int
+}
+
+type ArtistsResponse struct {
+	Data       []Artist `json:"data"`
+	Total      int      `json:"total"`
+	Page       int      `json:"page"`
+	PerPage    int      `json:"per_page"`
+	TotalPages int      `json:"total_pages"`
+}
+
+type AlbumsResponse struct {
+	Data       []Album `json:"data"`
+	Total      int     `json:"total"`
+	Page       int     `json:"page"`
+	PerPage    int     `json:"per_page"`
+	TotalPages int     `json:"total_pages"`
+}
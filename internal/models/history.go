@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// SongSnapshot - редактируемые поля песни на момент ревизии
+type SongSnapshot struct {
+	Title       string `json:"title"`
+	Artist      string `json:"artist"`
+	Album       string `json:"album"`
+	Genre       string `json:"genre"`
+	Duration    int    `json:"duration"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Link        string `json:"link,omitempty"`
+	TrackNumber *int   `json:"trackNumber,omitempty"`
+	DiscNumber  *int   `json:"discNumber,omitempty"`
+	Version     int    `json:"version"`
+}
+
+// SongRevision - запись истории изменений песни. Operation - create, update,
+// delete, restore, baseline для песен, созданных до ведения истории, а также
+// purge и merge для окончательно удаленной песни (After - ее последнее состояние)
+type SongRevision struct {
+	Revision  int    `json:"revision"`
+	Operation string `json:"operation"`
+	Actor     string `json:"actor,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	// Before не заполняется для создания песни
+	Before    *SongSnapshot `json:"before,omitempty"`
+	After     SongSnapshot  `json:"after"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+type RevisionsResponse struct {
+	Data       []SongRevision `json:"data"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	PerPage    int            `json:"per_page"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// DiffLine - строка построчного сравнения текста: op " " - без изменений,
+// "-" - удалена, "+" - добавлена
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// FieldChange - изменение поля песни между ревизиями. Для текста песни
+// дополнительно заполняется построчное сравнение Lines
+type FieldChange struct {
+	Field string     `json:"field"`
+	From  any        `json:"from"`
+	To    any        `json:"to"`
+	Lines []DiffLine `json:"lines,omitempty"`
+}
+
+// RevisionDiff - различия песни между ревизиями From и To
+type RevisionDiff struct {
+	SongID  int           `json:"songId"`
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
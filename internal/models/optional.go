@@ -0,0 +1,20 @@
+package models
+
+import "encoding/json"
+
+// Optional - поле запроса JSON Merge Patch (RFC 7396).
+// Set - поле присутствует в запросе, Null - передан null и значение нужно очистить
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Playlist - плейлист. TotalDuration - сумма длительностей песен в секундах,
+// песни из корзины в ней не учитываются
+type Playlist struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	SongCount     int       `json:"song_count"`
+	TotalDuration int       `json:"total_duration"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PlaylistInput - название и описание плейлиста
+type PlaylistInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PlaylistItem - песня на позиции плейлиста. Deleted означает, что песня в корзине:
+// она сохраняет место в плейлисте до восстановления или окончательного удаления
+type PlaylistItem struct {
+	ID       int       `json:"id"`
+	Position int       `json:"position"`
+	SongID   int       `json:"song_id"`
+	Title    string    `json:"title"`
+	Artist   string    `json:"artist"`
+	Duration int       `json:"duration"`
+	Deleted  bool      `json:"deleted,omitempty"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// PlaylistItemInput - песня, добавляемая в плейлист. Position задает позицию вставки,
+// 0 или позиция за последней - добавить в конец
+type PlaylistItemInput struct {
+	SongID   int `json:"song_id"`
+	Position int `json:"position,omitempty"`
+}
+
+// PlaylistItemMove - новая позиция элемента плейлиста
+type PlaylistItemMove struct {
+	Position int `json:"position"`
+}
+
+// PlaylistReorder - новый порядок всех элементов плейлиста
+type PlaylistReorder struct {
+	ItemIDs []int `json:"item_ids"`
+}
+
+type PlaylistsResponse struct {
+	Data       []Playlist `json:"data"`
+	Total      int        `json:"total"`
+	Page       int        `json:"page"`
+	PerPage    int        `json:"per_page"`
+	TotalPages int        `json:"total_pages"`
+}
+
+type PlaylistItemsResponse struct {
+	Data       []PlaylistItem `json:"data"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	PerPage    int            `json:"per_page"`
+	TotalPages int            `json:"total_pages"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// Problem - описание ошибки в формате RFC 7807 (application/problem+json).
+// Code - стабильный машиночитаемый код ошибки, Detail - сообщение для человека
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	// Role и RequiredRole заполняются для ответа 403
+	Role         string `json:"role,omitempty"`
+	RequiredRole string `json:"required_role,omitempty"`
+}
+
+// FieldError - ошибка валидации поля тела запроса или параметра запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
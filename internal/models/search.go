@@ -0,0 +1,20 @@
+package models
+
+// SearchResult - песня, найденная полнотекстовым поиском по тексту.
+// VerseNumber указывает совпавший куплет, если совпадение найдено в куплетах
+type SearchResult struct {
+	SongID      int     `json:"song_id"`
+	Title       string  `json:"title"`
+	Artist      string  `json:"artist"`
+	VerseNumber *int    `json:"verse_number,omitempty"`
+	Rank        float64 `json:"rank"`
+	Snippet     string  `json:"snippet"`
+}
+
+type SearchResponse struct {
+	Data       []SearchResult `json:"data"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	PerPage    int            `json:"per_page"`
+	TotalPages int            `json:"total_pages"`
+}
@@ -16,16 +16,58 @@ type Song struct {
 	Link        string    `json:"link,omitempty"`
 	CreatedAt   time.Time `json:"createdAt,omitempty"`
 	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	Version     int       `json:"version"`
+	// TrackNumber и DiscNumber - положение песни в альбоме
+	TrackNumber *int `json:"trackNumber,omitempty"`
+	DiscNumber  *int `json:"discNumber,omitempty"`
+	// DeletedAt заполняется только для песен в корзине
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
+// SortField - поле сортировки списка песен
+type SortField struct {
+	Name string
+	Desc bool
+}
+
+// SongFilter - параметры выборки списка песен. Необязательные условия
+// заданы указателями или пустыми значениями, nil означает "без фильтра"
 type SongFilter struct {
-	Title   string
-	Artist  string
-	Album   string
-	Year    int
-	Genre   string
-	Page    int
-	PerPage int
+	Title  string
+	Artist string
+	Album  string
+	Year   int
+	// Genres - подстроки жанра, песня подходит при совпадении с любой из них
+	Genres []string
+	// YearFrom и YearTo ограничивают год выпуска включительно
+	YearFrom *int
+	YearTo   *int
+	// ReleasedAfter и ReleasedBefore - даты ГГГГ-ММ-ДД, границы не включаются
+	ReleasedAfter  string
+	ReleasedBefore string
+	// DurationMin и DurationMax ограничивают длительность в секундах включительно
+	DurationMin *int
+	DurationMax *int
+	HasText     *bool
+	HasLink     *bool
+	// CreatedSince и UpdatedSince отбирают песни, созданные или измененные не раньше момента
+	CreatedSince *time.Time
+	UpdatedSince *time.Time
+	Page         int
+	PerPage      int
+	// Order - направление по умолчанию для полей без префикса "-"
+	Order string
+	// Sort - поля сортировки в порядке приоритета
+	Sort []SortField
+	// UseCursor включает курсорную пагинацию вместо page, Cursor пуст на первой странице
+	UseCursor bool
+	Cursor    string
+	// IncludeTotal запрашивает общее количество песен, требует отдельного подсчета
+	IncludeTotal bool
+	// Fuzzy включает поиск с опечатками по названию, исполнителю и альбому
+	Fuzzy bool
+	// Translit дополнительно ищет написание запроса в другом алфавите
+	Translit bool
 }
 
 type SongUpdate struct {
@@ -39,12 +81,36 @@ type SongUpdate struct {
 	Link        string `json:"link,omitempty"`
 }
 
+// SongPatch - частичное обновление песни: отсутствующие поля не меняются, null очищает значение
+type SongPatch struct {
+	Title       Optional[string] `json:"title" swaggertype:"string"`
+	Artist      Optional[string] `json:"artist" swaggertype:"string"`
+	Album       Optional[string] `json:"album" swaggertype:"string"`
+	Genre       Optional[string] `json:"genre" swaggertype:"string"`
+	Duration    Optional[int]    `json:"duration" swaggertype:"integer"`
+	ReleaseDate Optional[string] `json:"releaseDate" swaggertype:"string"`
+	Text        Optional[string] `json:"text" swaggertype:"string"`
+	Link        Optional[string] `json:"link" swaggertype:"string"`
+	TrackNumber Optional[int]    `json:"trackNumber" swaggertype:"integer"`
+	DiscNumber  Optional[int]    `json:"discNumber" swaggertype:"integer"`
+}
+
+// PaginatedResponse - страница песен. В курсорном режиме page не заполняется,
+// total и total_pages возвращаются только при include_total=true
 type PaginatedResponse struct {
 	Data       []Song `json:"data"`
-	Total      int    `json:"total"`
-	Page       int    `json:"page"`
+	Total      *int   `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
 	PerPage    int    `json:"per_page"`
-	TotalPages int    `json:"total_pages"`
+	TotalPages *int   `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// SongDetail - информация о песне, получаемая из внешнего сервиса
+type SongDetail struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
 }
 
 // Добавим новую структуру для упрощенного формата
@@ -52,3 +118,47 @@ type SimpleSongInput struct {
 	Group string `json:"group"`
 	Song  string `json:"song"`
 }
+
+// ImportRowResult - результат импорта одной строки: created, skipped или failed.
+// ID не заполняется при пробном запуске
+type ImportRowResult struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Artist string `json:"artist,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// SongExport - песня в выгрузке, Verses заполняются только по запросу
+type SongExport struct {
+	Song
+	Verses []Verse `json:"verses,omitempty"`
+}
+
+// DuplicateGroup - песни с одинаковым нормализованным названием и исполнителем
+type DuplicateGroup struct {
+	Key   string `json:"key"`
+	Songs []Song `json:"songs"`
+}
+
+type DuplicatesResponse struct {
+	Data       []DuplicateGroup `json:"data"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	PerPage    int              `json:"per_page"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// SongMerge - дубликат, объединяемый с песней из пути запроса и затем удаляемый
+type SongMerge struct {
+	DuplicateID int `json:"duplicate_id"`
+}
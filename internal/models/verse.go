@@ -3,9 +3,33 @@ package models
 import "time"
 
 type Verse struct {
-	ID          int       `json:"id"`
-	SongID      int       `json:"song_id"`
-	VerseNumber int       `json:"verse_number"`
-	Content     string    `json:"content"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int    `json:"id"`
+	SongID      int    `json:"song_id"`
+	VerseNumber int    `json:"verse_number"`
+	Type        string `json:"type"`
+	Content     string `json:"content"`
+	// StartMs и EndMs - время куплета в миллисекундах от начала песни
+	StartMs   *int      `json:"start_ms,omitempty"`
+	EndMs     *int      `json:"end_ms,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// VerseInput - данные для создания и изменения куплета.
+// Type - название из таблицы verse_types (verse, chorus, bridge, ...), по умолчанию verse.
+// VerseNumber при создании задает позицию вставки, 0 - добавить в конец.
+// SongID читается из тела только устаревшим маршрутом /verses/create.
+// StartMs и EndMs необязательны и проверяются вместе с метками остальных куплетов песни
+type VerseInput struct {
+	SongID      int    `json:"song_id,omitempty"`
+	VerseNumber int    `json:"verse_number,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Content     string `json:"content"`
+	StartMs     *int   `json:"start_ms,omitempty"`
+	EndMs       *int   `json:"end_ms,omitempty"`
+}
+
+// VerseMove - новая позиция куплета внутри песни
+type VerseMove struct {
+	Position int `json:"position"`
 }
@@ -0,0 +1,45 @@
+package problem
+
+import (
+	"net/http"
+
+	"song-library/internal/constants"
+	"song-library/internal/importer"
+	"song-library/internal/lyrics"
+	"song-library/internal/repository"
+	"song-library/internal/songinfo"
+)
+
+// sentinels сопоставляет ошибки репозитория и других пакетов статусам и кодам ответа.
+// Порядок важен: проверяется первая подходящая ошибка
+var sentinels = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{repository.ErrSongNotFound, http.StatusNotFound, constants.CodeSongNotFound},
+	{repository.ErrSongNotInTrash, http.StatusNotFound, constants.CodeSongNotInTrash},
+	{repository.ErrVerseNotFound, http.StatusNotFound, constants.CodeVerseNotFound},
+	{repository.ErrArtistNotFound, http.StatusNotFound, constants.CodeArtistNotFound},
+	{repository.ErrAlbumNotFound, http.StatusNotFound, constants.CodeAlbumNotFound},
+	{repository.ErrGenreNotFound, http.StatusNotFound, constants.CodeGenreNotFound},
+	{repository.ErrRevisionNotFound, http.StatusNotFound, constants.CodeRevisionNotFound},
+	{repository.ErrPlaylistNotFound, http.StatusNotFound, constants.CodePlaylistNotFound},
+	{repository.ErrPlaylistItemNotFound, http.StatusNotFound, constants.CodePlaylistItemNotFound},
+	{repository.ErrAPIKeyNotFound, http.StatusNotFound, constants.CodeAPIKeyNotFound},
+	{repository.ErrDuplicateSong, http.StatusConflict, constants.CodeDuplicateSong},
+	{repository.ErrDuplicatesExist, http.StatusConflict, constants.CodeDuplicatesExist},
+	{repository.ErrAPIKeyExists, http.StatusConflict, constants.CodeAPIKeyExists},
+	{repository.ErrVersionMismatch, http.StatusPreconditionFailed, constants.CodeVersionMismatch},
+	{repository.ErrMergeSameSong, http.StatusBadRequest, constants.CodeMergeSameSong},
+	{repository.ErrInvalidCursor, http.StatusBadRequest, constants.CodeInvalidCursor},
+	{repository.ErrUnknownVerseType, http.StatusBadRequest, constants.CodeUnknownVerseType},
+	{repository.ErrInvalidPlaylistOrder, http.StatusBadRequest, constants.CodeInvalidPlaylistOrder},
+	{lyrics.ErrInvalidTimings, http.StatusBadRequest, constants.CodeInvalidTimings},
+	{lyrics.ErrInvalidLRC, http.StatusBadRequest, constants.CodeInvalidLRC},
+	{lyrics.ErrNoTimings, http.StatusUnprocessableEntity, constants.CodeNoTimings},
+	{importer.ErrInvalidInput, http.StatusBadRequest, constants.CodeInvalidImport},
+	{songinfo.ErrNotFound, http.StatusNotFound, constants.CodeSongInfoNotFound},
+	{songinfo.ErrTimeout, http.StatusGatewayTimeout, constants.CodeSongInfoTimeout},
+	{songinfo.ErrMalformed, http.StatusBadGateway, constants.CodeSongInfoMalformed},
+}
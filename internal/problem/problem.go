@@ -0,0 +1,98 @@
+// Package problem формирует ответы об ошибках в формате RFC 7807
+// (application/problem+json) со стабильными машиночитаемыми кодами
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+
+	"golang.org/x/text/language"
+)
+
+// Error - замена http.Error: отвечает статусом status с кодом ошибки code
+// и сообщением detail
+func Error(w http.ResponseWriter, code, detail string, status int) {
+	write(w, models.Problem{Status: status, Code: code}, errors.New(detail))
+}
+
+// Internal отвечает 500 с сообщением detail, причина ошибки пишется только в журнал
+func Internal(w http.ResponseWriter, detail string) {
+	Error(w, constants.CodeInternalError, detail, http.StatusInternalServerError)
+}
+
+// FromError отвечает на ошибку err: ошибка валидации - 400 validation_failed со
+// списком полей, известная ошибка репозитория или другого пакета - статусом и кодом
+// из таблицы ошибок, остальные ошибки - статусом status, кодом по статусу и текстом ошибки
+func FromError(w http.ResponseWriter, err error, status int) {
+	if Known(w, err) {
+		return
+	}
+	write(w, models.Problem{Status: status, Code: statusCode(status)}, err)
+}
+
+// Known отвечает на ошибку валидации или ошибку из таблицы ошибок и возвращает true,
+// для прочих ошибок ничего не пишет и возвращает false
+func Known(w http.ResponseWriter, err error) bool {
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		writeValidation(w, validation)
+		return true
+	}
+	for _, known := range sentinels {
+		if errors.Is(err, known.err) {
+			write(w, models.Problem{Status: known.status, Code: known.code}, err)
+			return true
+		}
+	}
+	return false
+}
+
+// Forbidden отвечает 403 с ролью клиента и ролью, необходимой для маршрута
+func Forbidden(w http.ResponseWriter, role, required string) {
+	write(w, models.Problem{
+		Status:       http.StatusForbidden,
+		Code:         constants.CodeForbidden,
+		Role:         role,
+		RequiredRole: required,
+	}, i18n.Errorf(constants.ErrForbidden, required))
+}
+
+func writeValidation(w http.ResponseWriter, validation *ValidationError) {
+	write(w, models.Problem{
+		Status: http.StatusBadRequest,
+		Code:   constants.CodeValidationFailed,
+		Errors: validation.FieldErrors(responseLanguage(w)),
+	}, errors.New(constants.ErrValidationFailed))
+}
+
+// write отвечает проблемой p с сообщением detail на языке ответа
+func write(w http.ResponseWriter, p models.Problem, detail error) {
+	p.Detail = i18n.Localize(responseLanguage(w), detail)
+
+	p.Type = fmt.Sprintf(constants.ProblemTypeFormat, p.Code)
+	p.Title = http.StatusText(p.Status)
+	p.RequestID = w.Header().Get(constants.HeaderRequestID)
+
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeProblem)
+	w.Header().Del(constants.HeaderETag)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// responseLanguage возвращает язык, выбранный middleware.Language и записанный
+// в заголовок Content-Language
+func responseLanguage(w http.ResponseWriter) language.Tag {
+	return i18n.Match(w.Header().Get(constants.HeaderContentLanguage))
+}
+
+// statusCode формирует код ошибки из текста статуса, например not_found
+func statusCode(status int) string {
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", constants.ProblemCodeSeparator))
+}
@@ -0,0 +1,65 @@
+package problem
+
+import (
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+
+	"golang.org/x/text/language"
+)
+
+// ValidationError - ошибка валидации одного или нескольких полей запроса.
+// Сообщения хранятся ключом каталога и аргументами и форматируются на языке ответа
+type ValidationError struct {
+	fields []fieldError
+}
+
+type fieldError struct {
+	field   string
+	message error
+}
+
+// Invalid возвращает ошибку валидации поля или параметра field с сообщением
+// каталога key и его аргументами
+func Invalid(field, key string, args ...any) error {
+	e := &ValidationError{}
+	e.Add(field, key, args...)
+	return e
+}
+
+// Add добавляет ошибку поля field с сообщением каталога key
+func (e *ValidationError) Add(field, key string, args ...any) {
+	e.fields = append(e.fields, fieldError{field: field, message: i18n.Errorf(key, args...)})
+}
+
+// Err возвращает ошибку, если добавлено хотя бы одно поле, иначе nil
+func (e *ValidationError) Err() error {
+	if len(e.fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// FieldErrors возвращает ошибки полей с сообщениями на языке tag
+func (e *ValidationError) FieldErrors(tag language.Tag) []models.FieldError {
+	fields := make([]models.FieldError, len(e.fields))
+	for i, field := range e.fields {
+		fields[i] = models.FieldError{Field: field.field, Message: i18n.Localize(tag, field.message)}
+	}
+	return fields
+}
+
+// Localize объединяет сообщения всех полей на языке tag
+func (e *ValidationError) Localize(tag language.Tag) string {
+	messages := make([]string, len(e.fields))
+	for i, field := range e.fields {
+		messages[i] = i18n.Localize(tag, field.message)
+	}
+	return strings.Join(messages, constants.ValidationSeparator)
+}
+
+func (e *ValidationError) Error() string {
+	return e.Localize(i18n.Source)
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"song-library/internal/constants"
+)
+
+// ClientIP возвращает адрес клиента запроса. X-Forwarded-For учитывается, только
+// если запрос пришел от доверенного прокси: адреса перебираются справа налево,
+// и первый адрес не из trustedProxies считается адресом клиента
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	remote := remoteAddr(r.RemoteAddr)
+	if !remote.IsValid() {
+		return r.RemoteAddr
+	}
+	if !trusted(remote, trustedProxies) {
+		return remote.String()
+	}
+
+	client := remote
+	forwarded := strings.Split(strings.Join(r.Header.Values(constants.HeaderForwardedFor), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap()
+		if !trusted(client, trustedProxies) {
+			break
+		}
+	}
+	return client.String()
+}
+
+func remoteAddr(hostport string) netip.Addr {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+func trusted(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"song-library/internal/constants"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		{"без прокси", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"недоверенный адрес игнорирует заголовок", "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"доверенный прокси", "10.0.0.1:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"цепочка прокси", "10.0.0.1:5000", []string{"198.51.100.1, 10.0.0.2, 10.0.0.3"}, "198.51.100.1"},
+		{"подделанный адрес левее клиента", "10.0.0.1:5000", []string{"1.2.3.4, 198.51.100.1, 10.0.0.2"}, "198.51.100.1"},
+		{"несколько заголовков", "10.0.0.1:5000", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+		{"пробелы", "10.0.0.1:5000", []string{" 198.51.100.1 ,10.0.0.2 "}, "198.51.100.1"},
+		{"мусор в заголовке", "10.0.0.1:5000", []string{"198.51.100.1, garbage"}, "10.0.0.1"},
+		{"мусор левее клиента", "10.0.0.1:5000", []string{"garbage, 198.51.100.1"}, "198.51.100.1"},
+		{"все адреса доверенные", "10.0.0.1:5000", []string{"10.0.0.2"}, "10.0.0.2"},
+		{"без заголовка от прокси", "10.0.0.1:5000", nil, "10.0.0.1"},
+		{"IPv6 прокси", "[::1]:5000", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"IPv4 в IPv6", "[::ffff:10.0.0.1]:5000", []string{"::ffff:198.51.100.1"}, "198.51.100.1"},
+		{"адрес без порта", "203.0.113.7", nil, "203.0.113.7"},
+		{"не адрес", "pipe", []string{"198.51.100.1"}, "pipe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/songs", nil)
+			r.RemoteAddr = tt.remote
+			for _, value := range tt.forwarded {
+				r.Header.Add(constants.HeaderForwardedFor, value)
+			}
+			if got := ClientIP(r, proxies); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Без доверенных прокси заголовок не учитывается ни для какого адреса
+func TestClientIPWithoutTrustedProxies(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/songs", nil)
+	r.RemoteAddr = "10.0.0.1:5000"
+	r.Header.Set(constants.HeaderForwardedFor, "198.51.100.1")
+	if got := ClientIP(r, nil); got != "10.0.0.1" {
+		t.Errorf("ClientIP() = %q, want 10.0.0.1", got)
+	}
+}
@@ -0,0 +1,97 @@
+// Package ratelimit ограничивает частоту запросов клиентов алгоритмом token bucket
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"song-library/internal/constants"
+)
+
+// Result - решение ограничителя и данные для заголовков RateLimit-*
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset - через сколько корзина клиента снова заполнится полностью
+	Reset time.Duration
+	// RetryAfter - через сколько появится следующий токен, если запрос отклонен
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter выдает каждому клиенту корзину на burst токенов, которая пополняется
+// со скоростью rate токенов в секунду. Запрос расходует один токен
+type Limiter struct {
+	rate  float64
+	burst int
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New создает ограничитель со скоростью rate запросов в секунду и запасом burst
+func New(rate float64, burst int) *Limiter {
+	return &Limiter{
+		rate:      rate,
+		burst:     burst,
+		now:       time.Now,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow расходует токен клиента key, если он есть
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+
+	result := Result{Limit: l.burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.duration(1 - b.tokens)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = l.duration(float64(l.burst) - b.tokens)
+	return result
+}
+
+// sweep удаляет корзины клиентов, которые успели заполниться полностью:
+// новая корзина для такого клиента ничем от них не отличается
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < constants.RateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	full := l.duration(float64(l.burst))
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// duration возвращает время, за которое накопится tokens токенов
+func (l *Limiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"song-library/internal/constants"
+)
+
+// testLimiter возвращает ограничитель с часами, которые двигает advance
+func testLimiter(rate float64, burst int) (*Limiter, func(time.Duration)) {
+	now := time.Unix(1_700_000_000, 0)
+	l := New(rate, burst)
+	l.now = func() time.Time { return now }
+	l.lastSweep = now
+	return l, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestLimiterBurst(t *testing.T) {
+	l, _ := testLimiter(1, 3)
+
+	for n := 2; n >= 0; n-- {
+		result := l.Allow("a")
+		if !result.Allowed || result.Remaining != n || result.Limit != 3 {
+			t.Fatalf("Allow() = %+v, want allowed with %d remaining", result, n)
+		}
+	}
+	result := l.Allow("a")
+	if result.Allowed || result.Remaining != 0 || result.RetryAfter != time.Second || result.Reset != 3*time.Second {
+		t.Errorf("Allow() after burst = %+v", result)
+	}
+
+	// корзины клиентов независимы
+	if result := l.Allow("b"); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("Allow(other) = %+v", result)
+	}
+}
+
+func TestLimiterRefill(t *testing.T) {
+	l, advance := testLimiter(2, 2)
+	l.Allow("a")
+	l.Allow("a")
+
+	advance(250 * time.Millisecond)
+	if result := l.Allow("a"); result.Allowed || result.RetryAfter != 250*time.Millisecond {
+		t.Fatalf("Allow() after 250ms = %+v, want retry after 250ms", result)
+	}
+
+	advance(250 * time.Millisecond)
+	if result := l.Allow("a"); !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Allow() after 500ms = %+v, want allowed", result)
+	}
+
+	// корзина не наполняется сверх burst
+	advance(time.Hour)
+	for range 2 {
+		if result := l.Allow("a"); !result.Allowed {
+			t.Fatalf("Allow() after an hour = %+v, want allowed", result)
+		}
+	}
+	if result := l.Allow("a"); result.Allowed {
+		t.Errorf("Allow() over burst = %+v, want rejected", result)
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	l, advance := testLimiter(1, 2)
+	l.Allow("idle")
+	l.Allow("busy")
+
+	// до интервала очистки корзины не удаляются
+	advance(constants.RateLimitSweepInterval - time.Second)
+	l.Allow("busy")
+	if len(l.buckets) != 2 {
+		t.Fatalf("buckets = %d before sweep interval, want 2", len(l.buckets))
+	}
+
+	// idle успел заполниться и удаляется, busy обращался секунду назад и остается
+	advance(time.Second)
+	l.Allow("busy")
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("full bucket was not evicted")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("active bucket was evicted")
+	}
+
+	// удаленный клиент получает полную корзину
+	if result := l.Allow("idle"); !result.Allowed || result.Remaining != 1 {
+		t.Errorf("Allow(evicted) = %+v", result)
+	}
+}
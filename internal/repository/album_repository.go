@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+
+	"song-library/internal/constants"
+	"song-library/internal/db"
+	"song-library/internal/models"
+)
+
+//go:embed queries/albums/*.sql
+var albumQueries embed.FS
+
+type AlbumRepository struct {
+	BaseRepository
+	db *db.Database
+}
+
+func NewAlbumRepository(db *db.Database) (*AlbumRepository, error) {
+	queries, err := loadQueries(albumQueries, constants.AlbumQueriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlbumRepository{
+		BaseRepository: BaseRepository{queries: queries},
+		db:             db,
+	}, nil
+}
+
+// ListAlbums возвращает альбомы, filter.ArtistID ограничивает выборку исполнителем
+func (r *AlbumRepository) ListAlbums(filter models.CatalogFilter) (*models.AlbumsResponse, error) {
+	rows, err := r.db.Query(r.queries[constants.QueryList],
+		filter.Query, filter.ArtistID, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	albums := []models.Album{}
+	var totalCount int
+	for rows.Next() {
+		a, err := scanAlbum(rows, &totalCount)
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.AlbumsResponse{
+		Data:       albums,
+		Total:      totalCount,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages(totalCount, filter.PerPage),
+	}, nil
+}
+
+func (r *AlbumRepository) GetAlbum(id int) (*models.Album, error) {
+	a, err := scanAlbum(r.db.QueryRow(r.queries[constants.QueryGet], id))
+	if err == sql.ErrNoRows {
+		return nil, ErrAlbumNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UpdateAlbum заменяет год выпуска и обложку альбома
+func (r *AlbumRepository) UpdateAlbum(id int, update models.AlbumUpdate) error {
+	err := r.db.QueryRow(r.queries[constants.QueryUpdate], id,
+		update.ReleaseYear, nullIfEmpty(update.CoverURL)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrAlbumNotFound
+	}
+	return err
+}
+
+// GetAlbumSongs возвращает треклист альбома по номерам диска и трека
+func (r *AlbumRepository) GetAlbumSongs(id, page, perPage int) (*models.PaginatedResponse, error) {
+	if _, err := r.GetAlbum(id); err != nil {
+		return nil, err
+	}
+	return querySongPage(r.db, r.queries[constants.QueryCatalogSongs], id, page, perPage)
+}
+
+func scanAlbum(row rowScanner, extra ...any) (models.Album, error) {
+	var a models.Album
+	var coverURL sql.NullString
+
+	dest := append([]any{&a.ID, &a.Title, &a.ArtistID, &a.ArtistName, &a.ReleaseYear,
+		&coverURL, &a.SongCount, &a.CreatedAt, &a.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return a, err
+	}
+	a.CoverURL = coverURL.String
+	return a, nil
+}
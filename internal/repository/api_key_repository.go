@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+
+	"song-library/internal/constants"
+	"song-library/internal/db"
+	"song-library/internal/models"
+
+	"github.com/lib/pq"
+)
+
+//go:embed queries/api_keys/*.sql
+var apiKeyQueries embed.FS
+
+type APIKeyRepository struct {
+	BaseRepository
+	db *db.Database
+}
+
+func NewAPIKeyRepository(db *db.Database) (*APIKeyRepository, error) {
+	queries, err := loadQueries(apiKeyQueries, constants.APIKeyQueriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIKeyRepository{
+		BaseRepository: BaseRepository{queries: queries},
+		db:             db,
+	}, nil
+}
+
+// CreateAPIKey сохраняет ключ с именем name и ролью role по его хешу. Имена ключей уникальны
+func (r *APIKeyRepository) CreateAPIKey(name, hash, prefix, role string) (*models.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(r.queries[constants.QueryCreateAPIKey], name, hash, prefix, role))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == constants.PQUniqueViolation {
+		return nil, ErrAPIKeyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) ListAPIKeys() ([]models.APIKey, error) {
+	rows, err := r.db.Query(r.queries[constants.QueryList])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// FindAPIKey возвращает действующий ключ по хешу и отмечает его использование
+func (r *APIKeyRepository) FindAPIKey(hash string) (*models.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(r.queries[constants.QueryFindAPIKey], hash))
+	if err == sql.ErrNoRows {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey отзывает ключ с именем name, отозванный ключ больше не принимается
+func (r *APIKeyRepository) RevokeAPIKey(name string) error {
+	result, err := r.db.Exec(r.queries[constants.QueryRevokeAPIKey], name)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Role, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt)
+	return key, err
+}
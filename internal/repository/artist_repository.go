@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/db"
+	"song-library/internal/models"
+)
+
+//go:embed queries/artists/*.sql
+var artistQueries embed.FS
+
+type ArtistRepository struct {
+	BaseRepository
+	db *db.Database
+}
+
+func NewArtistRepository(db *db.Database) (*ArtistRepository, error) {
+	queries, err := loadQueries(artistQueries, constants.ArtistQueriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArtistRepository{
+		BaseRepository: BaseRepository{queries: queries},
+		db:             db,
+	}, nil
+}
+
+func (r *ArtistRepository) ListArtists(filter models.CatalogFilter) (*models.ArtistsResponse, error) {
+	rows, err := r.db.Query(r.queries[constants.QueryList],
+		filter.Query, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	artists := []models.Artist{}
+	var totalCount int
+	for rows.Next() {
+		var a models.Artist
+		if err := rows.Scan(&a.ID, &a.Name, &a.SongCount, &a.CreatedAt, &totalCount); err != nil {
+			return nil, err
+		}
+		artists = append(artists, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.ArtistsResponse{
+		Data:       artists,
+		Total:      totalCount,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages(totalCount, filter.PerPage),
+	}, nil
+}
+
+func (r *ArtistRepository) GetArtist(id int) (*models.Artist, error) {
+	a := &models.Artist{}
+	err := r.db.QueryRow(r.queries[constants.QueryGet], id).Scan(&a.ID, &a.Name, &a.SongCount, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrArtistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// GetArtistSongs возвращает песни исполнителя, в том числе с его участием
+func (r *ArtistRepository) GetArtistSongs(id, page, perPage int) (*models.PaginatedResponse, error) {
+	if _, err := r.GetArtist(id); err != nil {
+		return nil, err
+	}
+	return querySongPage(r.db, r.queries[constants.QueryCatalogSongs], id, page, perPage)
+}
+
+// GetSongArtists возвращает основного и приглашенных исполнителей песни
+func (r *ArtistRepository) GetSongArtists(songID int) ([]models.SongArtist, error) {
+	rows, err := r.db.Query(r.queries[constants.QuerySongCredits], songID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	credits := []models.SongArtist{}
+	for rows.Next() {
+		var c models.SongArtist
+		if err := rows.Scan(&c.ArtistID, &c.Name, &c.Role, &c.Position); err != nil {
+			return nil, err
+		}
+		credits = append(credits, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(credits) == 0 {
+		// у любой песни есть основной исполнитель, пустой список - песни нет
+		return nil, ErrSongNotFound
+	}
+	return credits, nil
+}
+
+// SetFeaturing заменяет приглашенных исполнителей песни, создавая новых при необходимости
+func (r *ArtistRepository) SetFeaturing(songID int, names []string) error {
+	return withTransaction(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(r.queries[constants.QueryLockSong], songID).Scan(&songID)
+		if err == sql.ErrNoRows {
+			return ErrSongNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(r.queries[constants.QueryDeleteFeaturing], songID); err != nil {
+			return err
+		}
+
+		for i, name := range names {
+			var artistID int
+			if err := tx.QueryRow(r.queries[constants.QueryUpsertArtist], strings.TrimSpace(name)).Scan(&artistID); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(r.queries[constants.QueryAddFeaturing], songID, artistID, i+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
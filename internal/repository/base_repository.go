@@ -1,11 +1,16 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"embed"
 	"fmt"
 	"strings"
 
 	"song-library/internal/constants"
+	"song-library/internal/db"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
 )
 
 type BaseRepository struct {
@@ -16,7 +21,7 @@ func loadQueries(fs embed.FS, path string) (map[string]string, error) {
 	queries := make(map[string]string)
 	files, err := fs.ReadDir(path)
 	if err != nil {
-		return nil, fmt.Errorf(constants.ErrReadingDirectory, err)
+		return nil, i18n.Errorf(constants.ErrReadingDirectory, err)
 	}
 
 	for _, f := range files {
@@ -27,10 +32,81 @@ func loadQueries(fs embed.FS, path string) (map[string]string, error) {
 		filePath := path + "/" + f.Name()
 		content, err := fs.ReadFile(filePath)
 		if err != nil {
-			return nil, fmt.Errorf(constants.ErrReadingFile, f.Name(), err)
+			return nil, i18n.Errorf(constants.ErrReadingFile, f.Name(), err)
 		}
 		name := strings.TrimSuffix(f.Name(), constants.SQLExtension)
 		queries[name] = string(content)
 	}
 	return queries, nil
 }
+
+// withFragments подставляет в запросы общие части из fragments вместо {{имя}},
+// чтобы условия, повторяющиеся в нескольких запросах, были описаны один раз
+func withFragments(queries, fragments map[string]string) {
+	for name, query := range queries {
+		for fragment, text := range fragments {
+			placeholder := fmt.Sprintf(constants.SQLFragmentFormat, fragment)
+			query = strings.ReplaceAll(query, placeholder, fmt.Sprintf(constants.SQLGroupFormat, strings.TrimSpace(text)))
+		}
+		queries[name] = query
+	}
+}
+
+// querier - общий интерфейс *db.Database и *sql.Tx для запросов одной строки
+type querier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// nullIfEmpty превращает пустую строку в NULL, чтобы не нарушать типы колонок (DATE и т.п.)
+func nullIfEmpty(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// withTransaction выполняет fn в транзакции, откатывая ее при ошибке
+func withTransaction(database *db.Database, fn func(*sql.Tx) error) error {
+	return withTransactionContext(context.Background(), database, fn)
+}
+
+// withTransactionContext выполняет fn в транзакции, привязанной к ctx: при отмене
+// ctx транзакция откатывается и следующие запросы в ней завершаются ошибкой
+func withTransactionContext(ctx context.Context, database *db.Database, fn func(*sql.Tx) error) error {
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		return i18n.Errorf(constants.ErrTransactionStart, err)
+	}
+	defer tx.Rollback() // откатится только если не было commit
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return i18n.Errorf(constants.ErrTransactionCommit, err)
+	}
+	return nil
+}
+
+// querySongPage выполняет запрос песен справочника ownerID с параметрами
+// ($1 - ID, $2 - LIMIT, $3 - OFFSET) и общим количеством в последней колонке
+func querySongPage(database *db.Database, query string, ownerID, page, perPage int) (*models.PaginatedResponse, error) {
+	rows, err := database.Query(query, ownerID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totalCount int
+	songs, err := scanSongs(rows, &totalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PaginatedResponse{Data: songs, Page: page, PerPage: perPage}
+	setTotal(response, totalCount)
+	return response, nil
+}
+
+// totalPages возвращает количество страниц для total элементов
+func totalPages(total, perPage int) int {
+	return (total + perPage - 1) / perPage
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"song-library/internal/constants"
+)
+
+// Условия фильтра описаны один раз и подставляются во все запросы списка песен
+func TestSongQueryFragments(t *testing.T) {
+	repo, err := NewSongRepository(nil, nil, SongRepositoryOptions{})
+	if err != nil {
+		t.Fatalf("NewSongRepository() error = %v", err)
+	}
+	fragments, err := loadQueries(songQueries, constants.SongFragmentsPath)
+	if err != nil {
+		t.Fatalf("loadQueries() error = %v", err)
+	}
+
+	for name, query := range repo.queries {
+		for fragment := range fragments {
+			if placeholder := fmt.Sprintf(constants.SQLFragmentFormat, fragment); strings.Contains(query, placeholder) {
+				t.Errorf("%s: %s не подставлен", name, placeholder)
+			}
+		}
+	}
+
+	filter := strings.TrimSpace(fragments["filter"])
+	for _, name := range []string{constants.QueryListSongs, constants.QueryCountSongs, constants.QueryListSongsFuzzy, constants.QueryExportDeclare} {
+		if !strings.Contains(repo.queries[name], filter) {
+			t.Errorf("%s: нет общего фильтра", name)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"song-library/internal/constants"
+	"song-library/internal/models"
+)
+
+// keysetCursor - содержимое курсора: значения полей сортировки последней строки
+// страницы и ее id. Sort хранит сортировку, для которой курсор был выдан
+type keysetCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v,omitempty"`
+	ID     int      `json:"id"`
+}
+
+// encodeCursor упаковывает курсор в непрозрачную строку для клиента
+func encodeCursor(c keysetCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor разбирает курсор и проверяет, что он выдан для сортировки sort
+func decodeCursor(raw, sort string, values int) (keysetCursor, error) {
+	var c keysetCursor
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	if c.Sort != sort || len(c.Values) != values {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// sortSignature описывает сортировку строкой вида "artist,-release_date"
+func sortSignature(sort []models.SortField) string {
+	names := make([]string, len(sort))
+	for i, field := range sort {
+		names[i] = field.Name
+		if field.Desc {
+			names[i] = constants.SortDescPrefix + field.Name
+		}
+	}
+	return strings.Join(names, constants.SortSeparator)
+}
+
+// keysetCondition строит условие "строка после курсора" для полей sort и s.id:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... OR (k1 = v1 AND ... AND s.id > id).
+// Параметры нумеруются начиная с firstParam
+func keysetCondition(sort []models.SortField, c keysetCursor, firstParam int) (string, []any) {
+	args := make([]any, 0, len(sort)+1)
+	equals := make([]string, 0, len(sort))
+	branches := make([]string, 0, len(sort)+1)
+
+	for i, field := range sort {
+		column := songSortColumns[field.Name]
+		param := firstParam + len(args)
+		args = append(args, c.Values[i])
+
+		op := constants.SQLGreater
+		if field.Desc {
+			op = constants.SQLLess
+		}
+		branch := append(slices.Clone(equals), fmt.Sprintf(constants.SQLCompareFormat, column.expr, op, param, column.cast))
+		branches = append(branches, fmt.Sprintf(constants.SQLGroupFormat, strings.Join(branch, constants.SQLAnd)))
+		equals = append(equals, fmt.Sprintf(constants.SQLCompareFormat, column.expr, constants.SQLEqual, param, column.cast))
+	}
+
+	param := firstParam + len(args)
+	args = append(args, c.ID)
+	branch := append(equals, fmt.Sprintf(constants.SQLCompareFormat,
+		constants.SQLSortTiebreaker, constants.SQLGreater, param, constants.SQLTypeInt))
+	branches = append(branches, fmt.Sprintf(constants.SQLGroupFormat, strings.Join(branch, constants.SQLAnd)))
+
+	return fmt.Sprintf(constants.SQLGroupFormat, strings.Join(branches, constants.SQLOr)), args
+}
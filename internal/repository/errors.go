@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+
+	"song-library/internal/constants"
+)
+
+var (
+	ErrSongNotFound         = errors.New(constants.ErrSongNotFound)
+	ErrSongNotInTrash       = errors.New(constants.ErrSongNotInTrash)
+	ErrVerseNotFound        = errors.New(constants.ErrVerseNotFound)
+	ErrUnknownVerseType     = errors.New(constants.ErrUnknownVerseType)
+	ErrVersionMismatch      = errors.New(constants.ErrPreconditionFailed)
+	ErrInvalidCursor        = errors.New(constants.ErrInvalidCursor)
+	ErrArtistNotFound       = errors.New(constants.ErrArtistNotFound)
+	ErrAlbumNotFound        = errors.New(constants.ErrAlbumNotFound)
+	ErrGenreNotFound        = errors.New(constants.ErrGenreNotFound)
+	ErrDuplicateSong        = errors.New(constants.ErrDuplicateSong)
+	ErrMergeSameSong        = errors.New(constants.ErrMergeSameSong)
+	ErrDuplicatesExist      = errors.New(constants.ErrDuplicatesExist)
+	ErrRevisionNotFound     = errors.New(constants.ErrRevisionNotFound)
+	ErrPlaylistNotFound     = errors.New(constants.ErrPlaylistNotFound)
+	ErrPlaylistItemNotFound = errors.New(constants.ErrPlaylistItemNotFound)
+	ErrInvalidPlaylistOrder = errors.New(constants.ErrInvalidPlaylistOrder)
+	ErrAPIKeyNotFound       = errors.New(constants.ErrAPIKeyNotFound)
+	ErrAPIKeyExists         = errors.New(constants.ErrAPIKeyExists)
+)
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+
+	"song-library/internal/constants"
+	"song-library/internal/db"
+	"song-library/internal/models"
+)
+
+//go:embed queries/genres/*.sql
+var genreQueries embed.FS
+
+type GenreRepository struct {
+	BaseRepository
+	db *db.Database
+}
+
+func NewGenreRepository(db *db.Database) (*GenreRepository, error) {
+	queries, err := loadQueries(genreQueries, constants.GenreQueriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenreRepository{
+		BaseRepository: BaseRepository{queries: queries},
+		db:             db,
+	}, nil
+}
+
+// ListGenres возвращает все жанры: их немного, поэтому без пагинации
+func (r *GenreRepository) ListGenres() ([]models.Genre, error) {
+	rows, err := r.db.Query(r.queries[constants.QueryList])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		var g models.Genre
+		if err := rows.Scan(&g.ID, &g.Name, &g.SongCount); err != nil {
+			return nil, err
+		}
+		genres = append(genres, g)
+	}
+	return genres, rows.Err()
+}
+
+func (r *GenreRepository) GetGenre(id int) (*models.Genre, error) {
+	g := &models.Genre{}
+	err := r.db.QueryRow(r.queries[constants.QueryGet], id).Scan(&g.ID, &g.Name, &g.SongCount)
+	if err == sql.ErrNoRows {
+		return nil, ErrGenreNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (r *GenreRepository) GetGenreSongs(id, page, perPage int) (*models.PaginatedResponse, error) {
+	if _, err := r.GetGenre(id); err != nil {
+		return nil, err
+	}
+	return querySongPage(r.db, r.queries[constants.QueryCatalogSongs], id, page, perPage)
+}
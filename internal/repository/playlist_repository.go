@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"slices"
+
+	"song-library/internal/constants"
+	"song-library/internal/db"
+	"song-library/internal/models"
+
+	"github.com/lib/pq"
+)
+
+//go:embed queries/playlists/*.sql
+var playlistQueries embed.FS
+
+type PlaylistRepository struct {
+	BaseRepository
+	db *db.Database
+}
+
+func NewPlaylistRepository(db *db.Database) (*PlaylistRepository, error) {
+	queries, err := loadQueries(playlistQueries, constants.PlaylistQueriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlaylistRepository{
+		BaseRepository: BaseRepository{queries: queries},
+		db:             db,
+	}, nil
+}
+
+// ListPlaylists возвращает плейлисты по названию, filter.Query - часть названия
+func (r *PlaylistRepository) ListPlaylists(filter models.CatalogFilter) (*models.PlaylistsResponse, error) {
+	rows, err := r.db.Query(r.queries[constants.QueryList],
+		filter.Query, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	playlists := []models.Playlist{}
+	var totalCount int
+	for rows.Next() {
+		p, err := scanPlaylist(rows, &totalCount)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.PlaylistsResponse{
+		Data:       playlists,
+		Total:      totalCount,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages(totalCount, filter.PerPage),
+	}, nil
+}
+
+func (r *PlaylistRepository) GetPlaylist(id int) (*models.Playlist, error) {
+	p, err := scanPlaylist(r.db.QueryRow(r.queries[constants.QueryGet], id))
+	if err == sql.ErrNoRows {
+		return nil, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PlaylistRepository) CreatePlaylist(input models.PlaylistInput) (int, error) {
+	var id int
+	err := r.db.QueryRow(r.queries[constants.QueryCreatePlaylist],
+		input.Name, nullIfEmpty(input.Description)).Scan(&id)
+	return id, err
+}
+
+func (r *PlaylistRepository) UpdatePlaylist(id int, input models.PlaylistInput) error {
+	err := r.db.QueryRow(r.queries[constants.QueryUpdate], id,
+		input.Name, nullIfEmpty(input.Description)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrPlaylistNotFound
+	}
+	return err
+}
+
+// DeletePlaylist удаляет плейлист вместе с элементами, песни не затрагиваются
+func (r *PlaylistRepository) DeletePlaylist(id int) error {
+	result, err := r.db.Exec(r.queries[constants.QueryDeletePlaylist], id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrPlaylistNotFound
+	}
+	return nil
+}
+
+func scanPlaylist(row rowScanner, extra ...any) (models.Playlist, error) {
+	var p models.Playlist
+	var description sql.NullString
+
+	dest := append([]any{&p.ID, &p.Name, &description, &p.SongCount, &p.TotalDuration,
+		&p.CreatedAt, &p.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return p, err
+	}
+	p.Description = description.String
+	return p, nil
+}
+
+// ListItems возвращает страницу элементов плейлиста по позициям
+func (r *PlaylistRepository) ListItems(playlistID, page, perPage int) (*models.PlaylistItemsResponse, error) {
+	if _, err := r.GetPlaylist(playlistID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(r.queries[constants.QueryPlaylistItems], playlistID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.PlaylistItem{}
+	var totalCount int
+	for rows.Next() {
+		item, err := scanPlaylistItem(rows, &totalCount)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.PlaylistItemsResponse{
+		Data:       items,
+		Total:      totalCount,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages(totalCount, perPage),
+	}, nil
+}
+
+func scanPlaylistItem(row rowScanner, extra ...any) (models.PlaylistItem, error) {
+	var item models.PlaylistItem
+	dest := append([]any{&item.ID, &item.Position, &item.SongID, &item.Title, &item.Artist,
+		&item.Duration, &item.Deleted, &item.AddedAt}, extra...)
+	err := row.Scan(dest...)
+	return item, err
+}
+
+// AddItem вставляет песню на позицию input.Position, сдвигая последующие элементы.
+// Если позиция не указана или больше количества элементов, песня добавляется в конец.
+// Песню из корзины добавить нельзя
+func (r *PlaylistRepository) AddItem(playlistID int, input models.PlaylistItemInput) (*models.PlaylistItem, error) {
+	var item models.PlaylistItem
+	err := withTransaction(r.db, func(tx *sql.Tx) error {
+		ids, err := r.lockItems(tx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		var exists bool
+		if err := tx.QueryRow(r.queries[constants.QueryPlaylistSongExists], input.SongID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrSongNotFound
+		}
+
+		position := input.Position
+		if position < 1 || position > len(ids) {
+			position = len(ids) + 1
+		} else if _, err := tx.Exec(r.queries[constants.QueryShiftPlaylistItems], playlistID, position); err != nil {
+			return err
+		}
+
+		var id int
+		if err := tx.QueryRow(r.queries[constants.QueryAddPlaylistItem],
+			playlistID, input.SongID, position).Scan(&id); err != nil {
+			return err
+		}
+		item, err = scanPlaylistItem(tx.QueryRow(r.queries[constants.QueryGetPlaylistItem], id, playlistID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// RemoveItem удаляет элемент плейлиста, последующие элементы сдвигаются
+func (r *PlaylistRepository) RemoveItem(playlistID, itemID int) error {
+	return withTransaction(r.db, func(tx *sql.Tx) error {
+		if _, err := r.lockItems(tx, playlistID); err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(r.queries[constants.QueryDeletePlaylistItem], itemID, playlistID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrPlaylistItemNotFound
+		}
+		return nil
+	})
+}
+
+// MoveItem переносит элемент на позицию position, нумерация остается непрерывной.
+// Возвращает итоговую позицию: позиция за последней заменяется последней
+func (r *PlaylistRepository) MoveItem(playlistID, itemID, position int) (int, error) {
+	err := withTransaction(r.db, func(tx *sql.Tx) error {
+		ids, err := r.lockItems(tx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		current := slices.Index(ids, itemID)
+		if current == -1 {
+			return ErrPlaylistItemNotFound
+		}
+
+		if position > len(ids) {
+			position = len(ids)
+		}
+		ids = slices.Delete(ids, current, current+1)
+		ids = slices.Insert(ids, position-1, itemID)
+
+		_, err = tx.Exec(r.queries[constants.QueryReorderPlaylist], playlistID, pq.Array(ids))
+		return err
+	})
+	return position, err
+}
+
+// ReorderItems задает новый порядок элементов. itemIDs должен содержать
+// каждый элемент плейлиста ровно один раз
+func (r *PlaylistRepository) ReorderItems(playlistID int, itemIDs []int) error {
+	return withTransaction(r.db, func(tx *sql.Tx) error {
+		ids, err := r.lockItems(tx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		sorted := slices.Clone(itemIDs)
+		slices.Sort(sorted)
+		slices.Sort(ids)
+		if !slices.Equal(sorted, ids) {
+			return ErrInvalidPlaylistOrder
+		}
+
+		_, err = tx.Exec(r.queries[constants.QueryReorderPlaylist], playlistID, pq.Array(itemIDs))
+		return err
+	})
+}
+
+// lockItems блокирует плейлист и возвращает ID его элементов по порядку позиций
+func (r *PlaylistRepository) lockItems(tx *sql.Tx, playlistID int) ([]int, error) {
+	err := tx.QueryRow(r.queries[constants.QueryLockPlaylist], playlistID).Scan(&playlistID)
+	if err == sql.ErrNoRows {
+		return nil, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(r.queries[constants.QueryPlaylistItemIDs], playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
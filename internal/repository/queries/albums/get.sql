@@ -0,0 +1,7 @@
+SELECT al.id, al.title, al.artist_id, a.name, al.release_year, al.cover_url,
+    COUNT(s.id) AS song_count, al.created_at, al.updated_at
+FROM albums al
+JOIN artists a ON a.id = al.artist_id
+LEFT JOIN songs s ON s.album_id = al.id AND s.deleted_at IS NULL
+WHERE al.id = $1
+GROUP BY al.id, a.name;
@@ -0,0 +1,12 @@
+SELECT al.id, al.title, al.artist_id, a.name, al.release_year, al.cover_url,
+    COUNT(s.id) AS song_count, al.created_at, al.updated_at,
+    COUNT(*) OVER() as total_count
+FROM albums al
+JOIN artists a ON a.id = al.artist_id
+LEFT JOIN songs s ON s.album_id = al.id AND s.deleted_at IS NULL
+WHERE 
+    ($1 = '' OR al.normalized_title LIKE '%' || LOWER($1) || '%') AND
+    ($2 = 0 OR al.artist_id = $2)
+GROUP BY al.id, a.name
+ORDER BY al.title, al.id
+LIMIT $3 OFFSET $4;
@@ -0,0 +1,10 @@
+-- Треклист: по номеру диска и трека, песни без номера идут в конце
+SELECT
+    s.id, s.title, s.artist, s.album, s.genre, s.duration, s.release_date,
+    s.text, s.link, s.created_at, s.updated_at, s.version,
+    s.track_number, s.disc_number,
+    COUNT(*) OVER() as total_count
+FROM songs s
+WHERE s.album_id = $1 AND s.deleted_at IS NULL
+ORDER BY COALESCE(s.disc_number, 1), s.track_number NULLS LAST, s.title, s.id
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,4 @@
+UPDATE albums
+SET release_year = $2, cover_url = $3
+WHERE id = $1
+RETURNING id;
@@ -0,0 +1,3 @@
+INSERT INTO api_keys (name, key_hash, prefix, role)
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, prefix, role, created_at, last_used_at, revoked_at;
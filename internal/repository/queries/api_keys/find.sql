@@ -0,0 +1,5 @@
+-- Поиск действующего ключа по хешу заодно отмечает время последнего использования
+UPDATE api_keys
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE key_hash = $1 AND revoked_at IS NULL
+RETURNING id, name, prefix, role, created_at, last_used_at, revoked_at;
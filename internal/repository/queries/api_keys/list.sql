@@ -0,0 +1,3 @@
+SELECT id, name, prefix, role, created_at, last_used_at, revoked_at
+FROM api_keys
+ORDER BY name;
@@ -0,0 +1,3 @@
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE name = $1 AND revoked_at IS NULL;
@@ -0,0 +1,4 @@
+-- Основной исполнитель, указанный среди приглашенных, остается основным
+INSERT INTO song_artists (song_id, artist_id, role, position)
+VALUES ($1, $2, 'featuring', $3)
+ON CONFLICT (song_id, artist_id) DO NOTHING;
@@ -0,0 +1,5 @@
+SELECT a.id, a.name, sa.role, sa.position
+FROM song_artists sa
+JOIN artists a ON a.id = sa.artist_id
+WHERE sa.song_id = $1
+ORDER BY sa.position, a.name;
@@ -0,0 +1 @@
+DELETE FROM song_artists WHERE song_id = $1 AND role = 'featuring';
@@ -0,0 +1,6 @@
+SELECT a.id, a.name, COUNT(s.id) AS song_count, a.created_at
+FROM artists a
+LEFT JOIN song_artists sa ON sa.artist_id = a.id
+LEFT JOIN songs s ON s.id = sa.song_id AND s.deleted_at IS NULL
+WHERE a.id = $1
+GROUP BY a.id;
@@ -0,0 +1,10 @@
+-- Количество песен учитывает участие исполнителя в качестве приглашенного
+SELECT a.id, a.name, COUNT(s.id) AS song_count, a.created_at,
+    COUNT(*) OVER() as total_count
+FROM artists a
+LEFT JOIN song_artists sa ON sa.artist_id = a.id
+LEFT JOIN songs s ON s.id = sa.song_id AND s.deleted_at IS NULL
+WHERE ($1 = '' OR a.normalized_name LIKE '%' || artist_key($1) || '%')
+GROUP BY a.id
+ORDER BY a.name, a.id
+LIMIT $2 OFFSET $3;
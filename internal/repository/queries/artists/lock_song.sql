@@ -0,0 +1 @@
+SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
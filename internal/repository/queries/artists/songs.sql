@@ -0,0 +1,11 @@
+-- Песни исполнителя, включая песни, где он приглашенный
+SELECT
+    s.id, s.title, s.artist, s.album, s.genre, s.duration, s.release_date,
+    s.text, s.link, s.created_at, s.updated_at, s.version,
+    s.track_number, s.disc_number,
+    COUNT(*) OVER() as total_count
+FROM songs s
+JOIN song_artists sa ON sa.song_id = s.id
+WHERE sa.artist_id = $1 AND s.deleted_at IS NULL
+ORDER BY s.release_date NULLS LAST, s.title, s.id
+LIMIT $2 OFFSET $3;
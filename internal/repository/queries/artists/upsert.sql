@@ -0,0 +1,6 @@
+-- Повторное сохранение существующего исполнителя, в том числе латиницей
+-- ("Kino" для "Кино"), не меняет его написание
+INSERT INTO artists (name, normalized_name)
+VALUES (TRIM($1), artist_key($1))
+ON CONFLICT (normalized_name) DO UPDATE SET name = artists.name
+RETURNING id;
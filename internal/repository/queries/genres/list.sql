@@ -0,0 +1,5 @@
+SELECT g.id, g.name, COUNT(s.id) AS song_count
+FROM genres g
+LEFT JOIN songs s ON s.genre_id = g.id AND s.deleted_at IS NULL
+GROUP BY g.id
+ORDER BY g.name, g.id;
@@ -0,0 +1,9 @@
+SELECT
+    s.id, s.title, s.artist, s.album, s.genre, s.duration, s.release_date,
+    s.text, s.link, s.created_at, s.updated_at, s.version,
+    s.track_number, s.disc_number,
+    COUNT(*) OVER() as total_count
+FROM songs s
+WHERE s.genre_id = $1 AND s.deleted_at IS NULL
+ORDER BY s.title, s.id
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,3 @@
+INSERT INTO playlist_items (playlist_id, song_id, position)
+VALUES ($1, $2, $3)
+RETURNING id;
@@ -0,0 +1,3 @@
+INSERT INTO playlists (name, description)
+VALUES ($1, $2)
+RETURNING id;
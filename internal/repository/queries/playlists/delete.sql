@@ -0,0 +1,2 @@
+DELETE FROM playlists
+WHERE id = $1;
@@ -0,0 +1,3 @@
+-- Разрыв в нумерации закрывает триггер renumber_playlist_items
+DELETE FROM playlist_items
+WHERE id = $1 AND playlist_id = $2;
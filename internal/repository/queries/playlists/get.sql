@@ -0,0 +1,9 @@
+SELECT p.id, p.name, p.description,
+    COUNT(pi.id) AS song_count,
+    COALESCE(SUM(s.duration) FILTER (WHERE s.deleted_at IS NULL), 0) AS total_duration,
+    p.created_at, p.updated_at
+FROM playlists p
+LEFT JOIN playlist_items pi ON pi.playlist_id = p.id
+LEFT JOIN songs s ON s.id = pi.song_id
+WHERE p.id = $1
+GROUP BY p.id;
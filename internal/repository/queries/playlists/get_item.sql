@@ -0,0 +1,5 @@
+SELECT pi.id, pi.position, s.id, s.title, s.artist, s.duration,
+    s.deleted_at IS NOT NULL AS deleted, pi.added_at
+FROM playlist_items pi
+JOIN songs s ON s.id = pi.song_id
+WHERE pi.id = $1 AND pi.playlist_id = $2;
@@ -0,0 +1,4 @@
+SELECT id
+FROM playlist_items
+WHERE playlist_id = $1
+ORDER BY position;
@@ -0,0 +1,9 @@
+-- Песни из корзины остаются на своих местах и возвращаются с признаком deleted
+SELECT pi.id, pi.position, s.id, s.title, s.artist, s.duration,
+    s.deleted_at IS NOT NULL AS deleted, pi.added_at,
+    COUNT(*) OVER() AS total_count
+FROM playlist_items pi
+JOIN songs s ON s.id = pi.song_id
+WHERE pi.playlist_id = $1
+ORDER BY pi.position
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,13 @@
+-- Общая длительность учитывает только песни вне корзины
+SELECT p.id, p.name, p.description,
+    COUNT(pi.id) AS song_count,
+    COALESCE(SUM(s.duration) FILTER (WHERE s.deleted_at IS NULL), 0) AS total_duration,
+    p.created_at, p.updated_at,
+    COUNT(*) OVER() AS total_count
+FROM playlists p
+LEFT JOIN playlist_items pi ON pi.playlist_id = p.id
+LEFT JOIN songs s ON s.id = pi.song_id
+WHERE $1 = '' OR LOWER(p.name) LIKE '%' || LOWER($1) || '%'
+GROUP BY p.id
+ORDER BY p.name, p.id
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,2 @@
+-- Блокируем плейлист, чтобы параллельные изменения не нарушили нумерацию элементов
+SELECT id FROM playlists WHERE id = $1 FOR UPDATE;
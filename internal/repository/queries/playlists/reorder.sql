@@ -0,0 +1,5 @@
+-- Позиции назначаются по порядку id в массиве
+UPDATE playlist_items pi
+SET position = o.position
+FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+WHERE pi.id = o.id AND pi.playlist_id = $1 AND pi.position <> o.position;
@@ -0,0 +1,3 @@
+UPDATE playlist_items
+SET position = position + 1
+WHERE playlist_id = $1 AND position >= $2;
@@ -0,0 +1,4 @@
+UPDATE playlists
+SET name = $2, description = $3
+WHERE id = $1
+RETURNING id;
@@ -0,0 +1,5 @@
+SELECT COUNT(*)
+FROM songs s
+WHERE 
+    {{match}} AND
+    {{filter}};
@@ -0,0 +1,3 @@
+-- Запрещает новые дубликаты среди песен вне корзины, не создается, пока в библиотеке есть дубликаты
+CREATE UNIQUE INDEX IF NOT EXISTS ux_songs_normalized_key ON songs(normalized_key)
+    WHERE deleted_at IS NULL;
@@ -1 +1,4 @@
-DELETE FROM songs WHERE id = $1; 
\ No newline at end of file
+-- Песня перемещается в корзину, окончательно удаляется фоновой очисткой (purge.sql)
+UPDATE songs
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL AND ($2::int[] IS NULL OR version = ANY($2::int[]));
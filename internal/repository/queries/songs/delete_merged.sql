@@ -0,0 +1,5 @@
+-- Объединенный дубликат удаляется окончательно, минуя корзину, чтобы его нельзя было
+-- восстановить рядом с основной песней. Оставшиеся связи с исполнителями удаляются
+-- каскадно, история изменений сохраняется и завершается ревизией merge
+DELETE FROM songs
+WHERE id = $1;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS ux_songs_normalized_key;
@@ -0,0 +1,9 @@
+-- Группы песен с одинаковым нормализованным ключом, самые многочисленные первыми.
+-- Последняя колонка - общее количество групп
+SELECT normalized_key, array_agg(id ORDER BY id), COUNT(*) OVER ()
+FROM songs
+WHERE deleted_at IS NULL
+GROUP BY normalized_key
+HAVING COUNT(*) > 1
+ORDER BY COUNT(*) DESC, normalized_key
+LIMIT $1 OFFSET $2;
@@ -0,0 +1 @@
+SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL);
@@ -0,0 +1,12 @@
+-- Серверный курсор выгрузки: строки читаются пакетами через export_fetch,
+-- курсор закрывается вместе с транзакцией
+DECLARE song_export NO SCROLL CURSOR FOR
+SELECT 
+    s.id, s.title, s.artist, s.album, s.genre, s.duration, s.release_date,
+    s.text, s.link, s.created_at, s.updated_at, s.version,
+    s.track_number, s.disc_number
+FROM songs s
+WHERE 
+    {{match}} AND
+    {{filter}}
+ORDER BY {{order_by}};
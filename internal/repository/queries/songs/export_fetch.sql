@@ -0,0 +1 @@
+FETCH FORWARD 500 FROM song_export;
@@ -0,0 +1,16 @@
+-- Фильтры списка песен $4-$15 (см. filterArgs), песни в корзине не выбираются
+deleted_at IS NULL AND
+($4 = 0 OR EXTRACT(YEAR FROM release_date) = $4) AND
+($5::text[] IS NULL OR EXISTS (
+    SELECT 1 FROM unnest($5::text[]) AS g(name)
+    WHERE LOWER(genre) LIKE LOWER('%' || g.name || '%'))) AND
+($6::int IS NULL OR EXTRACT(YEAR FROM release_date) >= $6) AND
+($7::int IS NULL OR EXTRACT(YEAR FROM release_date) <= $7) AND
+($8::date IS NULL OR release_date > $8) AND
+($9::date IS NULL OR release_date < $9) AND
+($10::int IS NULL OR duration >= $10) AND
+($11::int IS NULL OR duration <= $11) AND
+($12::boolean IS NULL OR (COALESCE(text, '') <> '') = $12) AND
+($13::boolean IS NULL OR (COALESCE(link, '') <> '') = $13) AND
+($14::timestamptz IS NULL OR created_at >= $14) AND
+($15::timestamptz IS NULL OR updated_at >= $15)
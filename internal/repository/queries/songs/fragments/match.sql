@@ -0,0 +1,4 @@
+-- Название, исполнитель и альбом $1-$3 ищутся подстрокой без учета регистра
+($1 = '' OR LOWER(title) LIKE LOWER('%' || $1 || '%')) AND
+($2 = '' OR LOWER(artist) LIKE LOWER('%' || $2 || '%')) AND
+($3 = '' OR LOWER(album) LIKE LOWER('%' || $3 || '%'))
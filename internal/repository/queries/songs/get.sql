@@ -1,3 +1,4 @@
-SELECT id, title, artist, album, genre, duration, release_date, text, link
+SELECT id, title, artist, album, genre, duration, release_date, text, link, created_at, updated_at, version,
+    track_number, disc_number
 FROM songs
-WHERE id = $1; 
\ No newline at end of file
+WHERE id = $1 AND deleted_at IS NULL;
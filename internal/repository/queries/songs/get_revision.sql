@@ -0,0 +1,3 @@
+SELECT revision, operation, actor, request_id, before, after, created_at
+FROM song_revisions
+WHERE song_id = $1 AND revision = $2;
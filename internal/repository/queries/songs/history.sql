@@ -0,0 +1,5 @@
+SELECT revision, operation, actor, request_id, before, after, created_at
+FROM song_revisions
+WHERE song_id = $1
+ORDER BY revision DESC
+LIMIT $2 OFFSET $3;
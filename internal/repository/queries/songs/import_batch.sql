@@ -0,0 +1,8 @@
+-- Пакетная вставка: $1-$10 - массивы значений колонок одинаковой длины
+INSERT INTO songs (title, artist, album, release_date, text, link, genre, duration, track_number, disc_number)
+SELECT * FROM unnest(
+    $1::text[], $2::text[], $3::text[], $4::date[],
+    $5::text[], $6::text[], $7::text[], $8::int[],
+    $9::int[], $10::int[]
+)
+RETURNING id, normalized_key;
@@ -0,0 +1,11 @@
+-- Ключи пар (название, исполнитель) из $1 и $2 в порядке массивов, вычисленные
+-- как normalized_key (миграция 009), и есть ли песня с таким ключом вне корзины.
+-- Поиск идет по индексу idx_songs_normalized_key
+SELECT k.key, EXISTS (
+    SELECT 1 FROM songs s WHERE s.normalized_key = k.key AND s.deleted_at IS NULL
+)
+FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS t(title, artist, n)
+CROSS JOIN LATERAL (
+    SELECT normalize_song_text(t.title) || ' - ' || normalize_song_text(t.artist) AS key
+) k
+ORDER BY t.n;
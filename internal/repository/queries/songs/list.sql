@@ -1,12 +1,13 @@
+-- Поля сортировки подставляются в ORDER BY, последним всегда идет s.id.
+-- Условие после WHERE ограничивает выборку строками после курсора (или TRUE)
 SELECT 
-    s.*, 
-    COUNT(*) OVER() as total_count
+    s.id, s.title, s.artist, s.album, s.genre, s.duration, s.release_date,
+    s.text, s.link, s.created_at, s.updated_at, s.version,
+    s.track_number, s.disc_number
 FROM songs s
 WHERE 
-    ($1 = '' OR LOWER(title) LIKE LOWER('%' || $1 || '%')) AND
-    ($2 = '' OR LOWER(artist) LIKE LOWER('%' || $2 || '%')) AND
-    ($3 = '' OR LOWER(album) LIKE LOWER('%' || $3 || '%')) AND
-    ($4 = 0 OR EXTRACT(YEAR FROM release_date) = $4) AND
-    ($5 = '' OR LOWER(genre) LIKE LOWER('%' || $5 || '%'))
-ORDER BY title
-LIMIT $6 OFFSET $7; 
\ No newline at end of file
+    {{match}} AND
+    {{filter}} AND
+    {{after}}
+ORDER BY {{order_by}}
+LIMIT $16 OFFSET $17; 
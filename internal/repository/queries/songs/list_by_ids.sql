@@ -0,0 +1,7 @@
+SELECT 
+    s.id, s.title, s.artist, s.album, s.genre, s.duration, s.release_date,
+    s.text, s.link, s.created_at, s.updated_at, s.version,
+    s.track_number, s.disc_number
+FROM songs s
+WHERE s.id = ANY($1::int[])
+ORDER BY s.id;
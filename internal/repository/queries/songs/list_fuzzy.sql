@@ -0,0 +1,26 @@
+-- Нечеткий поиск: $1-$3 - название, исполнитель и альбом из запроса,
+-- $4-$15 - общие фильтры списка (fragments/filter.sql),
+-- $16-$18 - транслитерированные варианты $1-$3 (пустая строка, если варианта нет).
+-- Порог сходства для <% задается через pg_trgm.word_similarity_threshold.
+-- В ORDER BY подставляются поля сортировки, затем s.similarity DESC и s.id
+SELECT
+    s.id, s.title, s.artist, s.album, s.genre, s.duration, s.release_date,
+    s.text, s.link, s.created_at, s.updated_at, s.version,
+    s.track_number, s.disc_number,
+    s.total_count
+FROM (
+    SELECT
+        songs.*,
+        COUNT(*) OVER() as total_count,
+        GREATEST(word_similarity($1, title), word_similarity($16, title)) +
+        GREATEST(word_similarity($2, artist), word_similarity($17, artist)) +
+        GREATEST(word_similarity($3, COALESCE(album, '')), word_similarity($18, COALESCE(album, ''))) as similarity
+    FROM songs
+    WHERE 
+        ($1 = '' OR $1 <% title OR ($16 <> '' AND $16 <% title)) AND
+        ($2 = '' OR $2 <% artist OR ($17 <> '' AND $17 <% artist)) AND
+        ($3 = '' OR $3 <% album OR ($18 <> '' AND $18 <% album)) AND
+        {{filter}}
+) s
+ORDER BY {{order_by}}
+LIMIT $19 OFFSET $20;
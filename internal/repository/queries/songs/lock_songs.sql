@@ -0,0 +1,2 @@
+-- Блокирует объединяемые песни в порядке id, чтобы встречные объединения не взаимоблокировались
+SELECT id FROM songs WHERE id = ANY($1::int[]) AND deleted_at IS NULL ORDER BY id FOR UPDATE;
@@ -0,0 +1,8 @@
+-- Приглашенные исполнители дубликата добавляются в конец списка основной песни
+INSERT INTO song_artists (song_id, artist_id, role, position)
+SELECT $1, d.artist_id, d.role,
+    (SELECT COALESCE(MAX(position), 0) FROM song_artists WHERE song_id = $1)
+        + ROW_NUMBER() OVER (ORDER BY d.position)
+FROM song_artists d
+WHERE d.song_id = $2 AND d.role = 'featuring'
+ON CONFLICT (song_id, artist_id) DO NOTHING;
@@ -0,0 +1,12 @@
+-- Незаполненные поля основной песни $1 берутся из дубликата $2
+UPDATE songs s
+SET album = COALESCE(s.album, d.album),
+    genre = COALESCE(s.genre, d.genre),
+    release_date = COALESCE(s.release_date, d.release_date),
+    text = COALESCE(s.text, d.text),
+    link = COALESCE(s.link, d.link),
+    duration = CASE WHEN s.duration = 0 THEN d.duration ELSE s.duration END,
+    track_number = COALESCE(s.track_number, d.track_number),
+    disc_number = COALESCE(s.disc_number, d.disc_number)
+FROM songs d
+WHERE s.id = $1 AND d.id = $2;
@@ -0,0 +1,4 @@
+-- Дубликат заменяется основной песней на тех же местах плейлистов
+UPDATE playlist_items
+SET song_id = $1
+WHERE song_id = $2;
@@ -0,0 +1,5 @@
+-- Куплеты дубликата $2 переносятся в конец основной песни $1 с сохранением порядка
+UPDATE verses
+SET song_id = $1,
+    verse_number = verse_number + COALESCE((SELECT MAX(verse_number) FROM verses WHERE song_id = $1), 0)
+WHERE song_id = $2;
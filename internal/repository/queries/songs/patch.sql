@@ -0,0 +1,7 @@
+-- Последняя колонка сообщает, изменился ли текст (см. update.sql)
+UPDATE songs
+SET {{set}}
+FROM (SELECT id AS old_id, text AS old_text FROM songs WHERE id = $1 FOR UPDATE) old
+WHERE id = old_id AND deleted_at IS NULL AND ($2::int[] IS NULL OR version = ANY($2::int[]))
+RETURNING id, title, artist, album, genre, duration, release_date, text, link, created_at, updated_at, version,
+    track_number, disc_number, text IS DISTINCT FROM old_text;
@@ -0,0 +1,5 @@
+-- Окончательно удаляет песни, пролежавшие в корзине дольше $1 секунд,
+-- куплеты и связи с исполнителями удаляются каскадно. История изменений
+-- сохраняется и завершается ревизией purge
+DELETE FROM songs
+WHERE deleted_at IS NOT NULL AND deleted_at < CURRENT_TIMESTAMP - make_interval(secs => $1);
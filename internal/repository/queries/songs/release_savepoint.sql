@@ -0,0 +1 @@
+RELEASE SAVEPOINT import_batch;
@@ -0,0 +1,5 @@
+UPDATE songs
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, title, artist, album, genre, duration, release_date, text, link, created_at, updated_at, version,
+    track_number, disc_number;
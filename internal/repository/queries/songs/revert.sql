@@ -0,0 +1,8 @@
+-- Последняя колонка сообщает, изменился ли текст (см. update.sql)
+UPDATE songs
+SET title = $3, artist = $4, album = $5, genre = $6, duration = $7,
+    release_date = $8, text = $9, link = $10, track_number = $11, disc_number = $12
+FROM (SELECT id AS old_id, text AS old_text FROM songs WHERE id = $1 FOR UPDATE) old
+WHERE id = old_id AND deleted_at IS NULL AND ($2::int[] IS NULL OR version = ANY($2::int[]))
+RETURNING id, title, artist, album, genre, duration, release_date, text, link, created_at, updated_at, version,
+    track_number, disc_number, text IS DISTINCT FROM old_text;
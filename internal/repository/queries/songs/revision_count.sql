@@ -0,0 +1,3 @@
+SELECT COUNT(*)
+FROM song_revisions
+WHERE song_id = $1;
@@ -0,0 +1 @@
+ROLLBACK TO SAVEPOINT import_batch;
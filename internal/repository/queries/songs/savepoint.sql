@@ -0,0 +1 @@
+SAVEPOINT import_batch;
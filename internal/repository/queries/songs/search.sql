@@ -0,0 +1,30 @@
+-- Совпадения ищутся в куплетах и в полном тексте песни. Для каждой песни
+-- остается одно совпадение: куплет предпочтительнее текста, так как дает номер куплета
+WITH query AS (
+    SELECT websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1) AS q
+),
+hits AS (
+    SELECT v.song_id, v.verse_number, v.content, ts_rank(v.search_vector, query.q) AS rank
+    FROM verses v, query
+    WHERE v.search_vector @@ query.q
+    UNION ALL
+    SELECT s.id, NULL, s.text, ts_rank(s.search_vector, query.q)
+    FROM songs s, query
+    WHERE s.search_vector @@ query.q AND s.deleted_at IS NULL
+),
+best AS (
+    SELECT DISTINCT ON (song_id) song_id, verse_number, content, rank
+    FROM hits
+    ORDER BY song_id, verse_number IS NULL, rank DESC
+)
+SELECT
+    s.id, s.title, s.artist, b.verse_number, b.rank,
+    ts_headline('russian', b.content, query.q,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20') AS snippet,
+    COUNT(*) OVER() AS total_count
+FROM best b
+JOIN songs s ON s.id = b.song_id
+CROSS JOIN query
+WHERE s.deleted_at IS NULL
+ORDER BY b.rank DESC, s.id
+LIMIT $2 OFFSET $3;
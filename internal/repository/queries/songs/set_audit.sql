@@ -0,0 +1,2 @@
+-- Настройки действуют до конца транзакции и читаются триггером record_song_revision
+SELECT set_config('app.actor', $1, true), set_config('app.request_id', $2, true);
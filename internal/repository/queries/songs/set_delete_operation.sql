@@ -0,0 +1,3 @@
+-- Операция ревизии, которой триггер record_song_revision отметит окончательное
+-- удаление песни в этой транзакции (по умолчанию purge)
+SELECT set_config('app.delete_operation', $1, true);
@@ -0,0 +1,2 @@
+-- Действует только до конца текущей транзакции
+SELECT set_config('pg_trgm.word_similarity_threshold', $1, true);
@@ -0,0 +1,11 @@
+-- Песни в корзине, недавно удаленные первыми. Последние колонки - время удаления
+-- и общее количество
+SELECT
+    s.id, s.title, s.artist, s.album, s.genre, s.duration, s.release_date,
+    s.text, s.link, s.created_at, s.updated_at, s.version,
+    s.track_number, s.disc_number,
+    s.deleted_at, COUNT(*) OVER() as total_count
+FROM songs s
+WHERE s.deleted_at IS NOT NULL
+ORDER BY s.deleted_at DESC, s.id
+LIMIT $1 OFFSET $2;
@@ -1,5 +1,8 @@
-UPDATE songs 
+-- Последняя колонка сообщает, изменился ли текст: куплеты пересобираются только
+-- тогда, чтобы не потерять заданные вручную типы, порядок и временные метки
+UPDATE songs
 SET title = $1, artist = $2, album = $3, release_date = $4, 
     text = $5, link = $6, genre = $7, duration = $8
-WHERE id = $9
-RETURNING id; 
\ No newline at end of file
+FROM (SELECT id AS old_id, text AS old_text FROM songs WHERE id = $9 FOR UPDATE) old
+WHERE id = old_id AND deleted_at IS NULL AND ($10::int[] IS NULL OR version = ANY($10::int[]))
+RETURNING version, text IS DISTINCT FROM old_text;
@@ -1,3 +1,3 @@
-INSERT INTO verses (song_id, verse_number, content)
-VALUES ($1, $2, $3)
-RETURNING id; 
\ No newline at end of file
+INSERT INTO verses (song_id, verse_number, verse_type_id, content, start_ms, end_ms)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
@@ -0,0 +1,5 @@
+-- Куплеты вставляются одним запросом, номера идут по порядку элементов массивов
+INSERT INTO verses (song_id, verse_number, verse_type_id, content, start_ms, end_ms)
+SELECT $1, b.position, b.type_id, b.content, b.start_ms, b.end_ms
+FROM unnest($2::int[], $3::text[], $4::int[], $5::int[])
+    WITH ORDINALITY AS b(type_id, content, start_ms, end_ms, position);
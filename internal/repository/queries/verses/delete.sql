@@ -0,0 +1,3 @@
+DELETE FROM verses
+WHERE id = $1
+RETURNING song_id, verse_number;
@@ -0,0 +1 @@
+DELETE FROM verses WHERE song_id = $1;
@@ -1,5 +1,7 @@
-SELECT id, song_id, verse_number, content, created_at
-FROM verses
-WHERE song_id = $1
-ORDER BY verse_number
-LIMIT $2 OFFSET $3; 
\ No newline at end of file
+SELECT v.id, v.song_id, v.verse_number, vt.name, v.content, v.start_ms, v.end_ms,
+    v.created_at, v.updated_at
+FROM verses v
+JOIN verse_types vt ON vt.id = v.verse_type_id
+WHERE v.song_id = $1
+ORDER BY v.verse_number
+LIMIT $2 OFFSET $3;
@@ -0,0 +1 @@
+SELECT duration FROM songs WHERE id = $1 AND deleted_at IS NULL;
@@ -0,0 +1 @@
+SELECT id FROM verse_types WHERE name = $1;
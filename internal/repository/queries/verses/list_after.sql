@@ -0,0 +1,8 @@
+-- Курсорная выборка: куплеты с номером больше $2, $3 - размер страницы
+SELECT v.id, v.song_id, v.verse_number, vt.name, v.content, v.start_ms, v.end_ms,
+    v.created_at, v.updated_at
+FROM verses v
+JOIN verse_types vt ON vt.id = v.verse_type_id
+WHERE v.song_id = $1 AND v.verse_number > $2
+ORDER BY v.verse_number
+LIMIT $3;
@@ -0,0 +1,7 @@
+-- Куплеты нескольких песен для выгрузки, сгруппированные по песне
+SELECT v.id, v.song_id, v.verse_number, vt.name, v.content, v.start_ms, v.end_ms,
+    v.created_at, v.updated_at
+FROM verses v
+JOIN verse_types vt ON vt.id = v.verse_type_id
+WHERE v.song_id = ANY($1::int[])
+ORDER BY v.song_id, v.verse_number;
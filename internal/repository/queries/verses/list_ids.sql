@@ -0,0 +1,4 @@
+SELECT id
+FROM verses
+WHERE song_id = $1
+ORDER BY verse_number;
@@ -0,0 +1,6 @@
+-- Временные метки куплетов песни по порядку и длительность песни для проверки
+SELECT v.start_ms, v.end_ms, s.duration
+FROM verses v
+JOIN songs s ON s.id = v.song_id
+WHERE v.song_id = $1
+ORDER BY v.verse_number;
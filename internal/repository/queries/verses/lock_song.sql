@@ -0,0 +1,2 @@
+-- Блокируем песню, чтобы параллельные изменения куплетов не нарушили нумерацию
+SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
@@ -0,0 +1,3 @@
+SELECT COALESCE(MAX(verse_number), 0) + 1
+FROM verses
+WHERE song_id = $1;
@@ -0,0 +1,5 @@
+-- Номера сначала делаются отрицательными, затем назначаются по порядку id в массиве
+UPDATE verses v
+SET verse_number = -o.position
+FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+WHERE v.id = o.id AND v.song_id = $1;
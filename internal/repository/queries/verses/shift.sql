@@ -0,0 +1,5 @@
+-- Сдвигает номера куплетов начиная с $2 на $3, временно делая их отрицательными,
+-- чтобы не нарушить ограничение unique_verse_number_per_song
+UPDATE verses
+SET verse_number = -(verse_number + $3)
+WHERE song_id = $1 AND verse_number >= $2;
@@ -0,0 +1,10 @@
+-- Текст песни собирается из куплетов ($2 - разделитель), чтобы поиск, выгрузка
+-- и история видели правки куплетов. Строка не меняется, если текст совпадает
+UPDATE songs s
+SET text = t.text
+FROM (
+    SELECT NULLIF(string_agg(content, $2 ORDER BY verse_number), '') AS text
+    FROM verses
+    WHERE song_id = $1
+) t
+WHERE s.id = $1 AND s.text IS DISTINCT FROM t.text;
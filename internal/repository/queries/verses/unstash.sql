@@ -0,0 +1,3 @@
+UPDATE verses
+SET verse_number = -verse_number
+WHERE song_id = $1 AND verse_number < 0;
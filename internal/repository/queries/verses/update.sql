@@ -0,0 +1,4 @@
+UPDATE verses
+SET verse_type_id = $2, content = $3, start_ms = $4, end_ms = $5
+WHERE id = $1
+RETURNING song_id;
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// EnsureUniqueKey создает или удаляет уникальный индекс по нормализованному ключу
+// песни. Индекс нельзя создать, пока в библиотеке остаются дубликаты
+func (r *SongRepository) EnsureUniqueKey(enabled bool) error {
+	if !enabled {
+		_, err := r.db.Exec(r.queries[constants.QueryDropUniqueKey])
+		return err
+	}
+
+	_, err := r.db.Exec(r.queries[constants.QueryCreateUniqueKey])
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == constants.PQUniqueViolation {
+		return i18n.Errorf(constants.ErrWrapFormat, ErrDuplicatesExist, err)
+	}
+	return err
+}
+
+// songWriteError заменяет нарушение уникального ключа песни на ErrDuplicateSong
+func songWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == constants.PQUniqueViolation &&
+		pqErr.Constraint == constants.SongUniqueKeyIndex {
+		return ErrDuplicateSong
+	}
+	return err
+}
+
+// ListDuplicates возвращает страницу групп песен-дубликатов, песни в группе упорядочены по id
+func (r *SongRepository) ListDuplicates(page, perPage int) (*models.DuplicatesResponse, error) {
+	rows, err := r.db.Query(r.queries[constants.QueryDuplicates], perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.DuplicateGroup{}
+	var ids [][]int64
+	var totalCount int
+	for rows.Next() {
+		var group models.DuplicateGroup
+		var groupIDs []int64
+		if err := rows.Scan(&group.Key, pq.Array(&groupIDs), &totalCount); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+		ids = append(ids, groupIDs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var allIDs []int64
+	for _, groupIDs := range ids {
+		allIDs = append(allIDs, groupIDs...)
+	}
+	songs, err := r.listByIDs(allIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, groupIDs := range ids {
+		for _, id := range groupIDs {
+			if song, ok := songs[int(id)]; ok {
+				groups[i].Songs = append(groups[i].Songs, song)
+			}
+		}
+	}
+
+	return &models.DuplicatesResponse{
+		Data:       groups,
+		Total:      totalCount,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages(totalCount, perPage),
+	}, nil
+}
+
+func (r *SongRepository) listByIDs(ids []int64) (map[int]models.Song, error) {
+	songs := make(map[int]models.Song, len(ids))
+	if len(ids) == 0 {
+		return songs, nil
+	}
+
+	rows, err := r.db.Query(r.queries[constants.QueryListSongsByIDs], pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list, err := scanSongs(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, song := range list {
+		songs[song.ID] = song
+	}
+	return songs, nil
+}
+
+// MergeSongs объединяет дубликат duplicateID с песней survivorID в одной транзакции:
+// незаполненные поля и приглашенные исполнители берутся из дубликата, его куплеты
+// добавляются после куплетов основной песни, в плейлистах дубликат заменяется
+// основной песней, затем дубликат удаляется окончательно, без перемещения в корзину.
+// История дубликата сохраняется и завершается ревизией merge.
+// Возвращает основную песню после объединения
+func (r *SongRepository) MergeSongs(ctx context.Context, survivorID, duplicateID int) (*models.Song, error) {
+	if survivorID == duplicateID {
+		return nil, ErrMergeSameSong
+	}
+
+	var song models.Song
+	err := r.withAudit(ctx, func(tx *sql.Tx) error {
+		if err := r.lockSongs(tx, survivorID, duplicateID); err != nil {
+			return err
+		}
+
+		for _, query := range []string{
+			constants.QueryMergeFields,
+			constants.QueryMergeArtists,
+			constants.QueryMergePlaylists,
+		} {
+			if _, err := tx.Exec(r.queries[query], survivorID, duplicateID); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.Exec(r.queries[constants.QueryMergeVerses], survivorID, duplicateID)
+		if err != nil {
+			return err
+		}
+		moved, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if moved > 0 {
+			// текст основной песни пересобирается вместе с перенесенными куплетами
+			if err := r.verses.syncSongText(tx, survivorID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(r.queries[constants.QuerySetDeleteOperation], constants.RevisionMerge); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(r.queries[constants.QueryDeleteMergedSong], duplicateID); err != nil {
+			return err
+		}
+
+		song, err = scanSong(tx.QueryRow(r.queries[constants.QueryGet], survivorID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// lockSongs блокирует песни ids и возвращает ErrSongNotFound, если какой-то из них нет
+func (r *SongRepository) lockSongs(tx *sql.Tx, ids ...int) error {
+	rows, err := tx.Query(r.queries[constants.QueryLockSongs], pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	found := 0
+	for rows.Next() {
+		found++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if found != len(ids) {
+		return ErrSongNotFound
+	}
+	return nil
+}
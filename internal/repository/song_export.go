@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"song-library/internal/constants"
+	"song-library/internal/models"
+)
+
+// ExportSongs передает в fn все песни, подходящие под filter, в порядке filter.Sort.
+// Строки читаются серверным курсором пакетами, поэтому вся выборка не держится в памяти.
+// Пагинация и нечеткий поиск фильтра не учитываются. При withVerses к каждой песне
+// добавляются куплеты. Ошибка fn или отмена ctx прерывает выгрузку
+func (r *SongRepository) ExportSongs(ctx context.Context, filter models.SongFilter, withVerses bool, fn func(models.SongExport) error) error {
+	return withTransactionContext(ctx, r.db, func(tx *sql.Tx) error {
+		declare := withOrderBy(r.queries[constants.QueryExportDeclare], filter.Sort)
+		if _, err := tx.ExecContext(ctx, declare, filterArgs(filter)...); err != nil {
+			return err
+		}
+
+		for {
+			songs, err := r.fetchExport(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if len(songs) == 0 {
+				return nil
+			}
+
+			var verses map[int][]models.Verse
+			if withVerses {
+				ids := make([]int, len(songs))
+				for i, s := range songs {
+					ids[i] = s.ID
+				}
+				if verses, err = r.verses.listBySongs(tx, ids); err != nil {
+					return err
+				}
+			}
+
+			for _, s := range songs {
+				if err := fn(models.SongExport{Song: s, Verses: verses[s.ID]}); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
+
+// fetchExport читает следующий пакет строк курсора выгрузки, пустой пакет - конец выборки
+func (r *SongRepository) fetchExport(ctx context.Context, tx *sql.Tx) ([]models.Song, error) {
+	rows, err := tx.QueryContext(ctx, r.queries[constants.QueryExportFetch])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSongs(rows)
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+
+	"song-library/internal/audit"
+	"song-library/internal/constants"
+	"song-library/internal/lyrics"
+	"song-library/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// snapshotField - поле снимка песни, сравниваемое в DiffRevisions
+type snapshotField struct {
+	name  string
+	value func(models.SongSnapshot) any
+}
+
+var snapshotFields = []snapshotField{
+	{constants.FieldTitle, func(s models.SongSnapshot) any { return s.Title }},
+	{constants.FieldArtist, func(s models.SongSnapshot) any { return s.Artist }},
+	{constants.FieldAlbum, func(s models.SongSnapshot) any { return s.Album }},
+	{constants.FieldGenre, func(s models.SongSnapshot) any { return s.Genre }},
+	{constants.FieldDuration, func(s models.SongSnapshot) any { return s.Duration }},
+	{constants.FieldReleaseDate, func(s models.SongSnapshot) any { return s.ReleaseDate }},
+	{constants.FieldText, func(s models.SongSnapshot) any { return s.Text }},
+	{constants.FieldLink, func(s models.SongSnapshot) any { return s.Link }},
+	{constants.FieldTrackNumber, func(s models.SongSnapshot) any { return optionalInt(s.TrackNumber) }},
+	{constants.FieldDiscNumber, func(s models.SongSnapshot) any { return optionalInt(s.DiscNumber) }},
+}
+
+func optionalInt(value *int) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// withAudit выполняет fn в транзакции, в которой триггер истории изменений
+// видит автора и ID запроса из ctx
+func (r *SongRepository) withAudit(ctx context.Context, fn func(*sql.Tx) error) error {
+	return withTransaction(r.db, func(tx *sql.Tx) error {
+		if err := setAudit(ctx, tx); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// setAuditQuery используется всеми репозиториями, чьи изменения попадают в историю песен
+//
+//go:embed queries/songs/set_audit.sql
+var setAuditQuery string
+
+// setAudit передает автора и ID запроса из ctx в настройки транзакции tx
+func setAudit(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(setAuditQuery, audit.Actor(ctx), audit.RequestID(ctx))
+	return err
+}
+
+// ListRevisions возвращает страницу истории изменений песни, новые ревизии первыми.
+// История доступна и для песен в корзине, и для окончательно удаленных
+func (r *SongRepository) ListRevisions(songID, page, perPage int) (*models.RevisionsResponse, error) {
+	var totalCount int
+	if err := r.db.QueryRow(r.queries[constants.QueryRevisionCount], songID).Scan(&totalCount); err != nil {
+		return nil, err
+	}
+	if totalCount == 0 {
+		return nil, ErrSongNotFound
+	}
+
+	rows, err := r.db.Query(r.queries[constants.QueryHistory], songID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.SongRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.RevisionsResponse{
+		Data:       revisions,
+		Total:      totalCount,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages(totalCount, perPage),
+	}, nil
+}
+
+// GetRevision возвращает ревизию revision песни songID
+func (r *SongRepository) GetRevision(songID, revision int) (*models.SongRevision, error) {
+	return r.getRevision(r.db, songID, revision)
+}
+
+func (r *SongRepository) getRevision(q querier, songID, revision int) (*models.SongRevision, error) {
+	rev, err := scanRevision(q.QueryRow(r.queries[constants.QueryGetRevision], songID, revision))
+	if err == sql.ErrNoRows {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// scanRevision читает колонки ревизии в порядке запросов history и get_revision
+func scanRevision(row rowScanner) (models.SongRevision, error) {
+	var rev models.SongRevision
+	var actor, requestID sql.NullString
+	var before, after []byte
+	if err := row.Scan(&rev.Revision, &rev.Operation, &actor, &requestID,
+		&before, &after, &rev.CreatedAt); err != nil {
+		return rev, err
+	}
+
+	rev.Actor = actor.String
+	rev.RequestID = requestID.String
+	if before != nil {
+		rev.Before = &models.SongSnapshot{}
+		if err := json.Unmarshal(before, rev.Before); err != nil {
+			return rev, err
+		}
+	}
+	return rev, json.Unmarshal(after, &rev.After)
+}
+
+// DiffRevisions сравнивает состояние песни после ревизий from и to.
+// Для текста песни возвращается также построчное сравнение
+func (r *SongRepository) DiffRevisions(songID, from, to int) (*models.RevisionDiff, error) {
+	fromRev, err := r.GetRevision(songID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := r.GetRevision(songID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.RevisionDiff{SongID: songID, From: from, To: to, Changes: []models.FieldChange{}}
+	for _, field := range snapshotFields {
+		before, after := field.value(fromRev.After), field.value(toRev.After)
+		if before == after {
+			continue
+		}
+		change := models.FieldChange{Field: field.name, From: before, To: after}
+		if field.name == constants.FieldText {
+			for _, line := range lyrics.DiffLines(fromRev.After.Text, toRev.After.Text) {
+				change.Lines = append(change.Lines, models.DiffLine{Op: line.Op, Text: line.Text})
+			}
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+	return diff, nil
+}
+
+// RevertSong возвращает поля песни к состоянию после ревизии revision и, если текст
+// изменился, пересобирает куплеты из восстановленного текста. Откат записывается
+// в историю новой ревизией. ifMatch работает так же, как в DeleteSong
+func (r *SongRepository) RevertSong(ctx context.Context, id, revision int, ifMatch []int) (*models.Song, error) {
+	var song models.Song
+	err := r.withAudit(ctx, func(tx *sql.Tx) error {
+		rev, err := r.getRevision(tx, id, revision)
+		if err != nil {
+			return err
+		}
+
+		s := rev.After
+		var textChanged bool
+		song, err = scanSong(tx.QueryRow(r.queries[constants.QueryRevertSong],
+			id, pq.Array(ifMatch),
+			s.Title, s.Artist, nullIfEmpty(s.Album), nullIfEmpty(s.Genre), s.Duration,
+			nullIfEmpty(s.ReleaseDate), nullIfEmpty(s.Text), nullIfEmpty(s.Link),
+			s.TrackNumber, s.DiscNumber), &textChanged)
+		if err == sql.ErrNoRows {
+			return r.missingOrModified(tx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		return r.resyncVerses(tx, id, s.Text, textChanged)
+	})
+	if err != nil {
+		return nil, songWriteError(err)
+	}
+	return &song, nil
+}
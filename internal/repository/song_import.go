@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"song-library/internal/constants"
+	"song-library/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// SongImport - пакетная вставка песен в рамках одной транзакции.
+// Каждый пакет выполняется под точкой сохранения, поэтому ошибка в пакете
+// не прерывает импорт остальных строк
+type SongImport struct {
+	repo *SongRepository
+	tx   *sql.Tx
+}
+
+// BeginImport открывает транзакцию импорта, ее нужно завершить Commit или Rollback.
+// Созданные песни попадают в историю изменений с автором и ID запроса из ctx
+func (r *SongRepository) BeginImport(ctx context.Context) (*SongImport, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if err := setAudit(ctx, tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return &SongImport{repo: r, tx: tx}, nil
+}
+
+// Keys возвращает ключи песен для поиска дубликатов в порядке songs - те же,
+// что normalized_key в таблице songs, - и набор ключей, которые уже есть в библиотеке
+func (i *SongImport) Keys(songs []models.Song) ([]string, map[string]bool, error) {
+	titles := make([]string, len(songs))
+	artists := make([]string, len(songs))
+	for n, s := range songs {
+		titles[n], artists[n] = s.Title, s.Artist
+	}
+
+	rows, err := i.tx.Query(i.repo.queries[constants.QueryImportExisting], pq.Array(titles), pq.Array(artists))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0, len(songs))
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		var exists bool
+		if err := rows.Scan(&key, &exists); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		if exists {
+			existing[key] = true
+		}
+	}
+	return keys, existing, rows.Err()
+}
+
+// Insert вставляет пакет песен одним запросом и разбивает их тексты на куплеты.
+// keys - ключи песен из Keys, они должны быть уникальны. Возвращает ID в порядке songs
+func (i *SongImport) Insert(songs []models.Song, keys []string) ([]int, error) {
+	if _, err := i.tx.Exec(i.repo.queries[constants.QuerySavepoint]); err != nil {
+		return nil, err
+	}
+
+	ids, err := i.insert(songs, keys)
+	if err != nil {
+		if _, rollbackErr := i.tx.Exec(i.repo.queries[constants.QueryRollbackSavepoint]); rollbackErr != nil {
+			return nil, rollbackErr
+		}
+		return nil, songWriteError(err)
+	}
+
+	if _, err := i.tx.Exec(i.repo.queries[constants.QueryReleaseSavepoint]); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (i *SongImport) insert(songs []models.Song, keys []string) ([]int, error) {
+	n := len(songs)
+	titles, artists := make([]string, n), make([]string, n)
+	albums, dates := make([]sql.NullString, n), make([]sql.NullString, n)
+	texts, links := make([]sql.NullString, n), make([]sql.NullString, n)
+	genres, durations := make([]sql.NullString, n), make([]int, n)
+	tracks, discs := make([]sql.NullInt64, n), make([]sql.NullInt64, n)
+	for k, s := range songs {
+		titles[k], artists[k] = s.Title, s.Artist
+		albums[k], dates[k] = nullIfEmpty(s.Album), nullIfEmpty(s.ReleaseDate)
+		texts[k], links[k] = nullIfEmpty(s.Text), nullIfEmpty(s.Link)
+		genres[k], durations[k] = nullIfEmpty(s.Genre), s.Duration
+		tracks[k], discs[k] = nullInt(s.TrackNumber), nullInt(s.DiscNumber)
+	}
+
+	rows, err := i.tx.Query(i.repo.queries[constants.QueryImportBatch],
+		pq.Array(titles), pq.Array(artists), pq.Array(albums), pq.Array(dates),
+		pq.Array(texts), pq.Array(links), pq.Array(genres), pq.Array(durations),
+		pq.Array(tracks), pq.Array(discs))
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING не гарантирует порядок строк, поэтому ID сопоставляются по ключу
+	created := make(map[string]int, n)
+	for rows.Next() {
+		var id int
+		var key string
+		if err := rows.Scan(&id, &key); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		created[key] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, n)
+	for k, s := range songs {
+		ids[k] = created[keys[k]]
+		if err := i.repo.syncVerses(i.tx, ids[k], s.Text); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+func (i *SongImport) Commit() error {
+	return i.tx.Commit()
+}
+
+// Rollback отменяет импорт, используется и для пробного запуска
+func (i *SongImport) Rollback() error {
+	return i.tx.Rollback()
+}
@@ -1,155 +1,541 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"embed"
 	"song-library/internal/constants"
 	"song-library/internal/db"
+	"song-library/internal/lyrics"
 	"song-library/internal/models"
+	"song-library/internal/translit"
+
+	"github.com/lib/pq"
 )
 
-//go:embed queries/songs/*.sql
+//go:embed queries/songs/*.sql queries/songs/fragments/*.sql
 var songQueries embed.FS
 
 type SongRepository struct {
 	BaseRepository
-	db *db.Database
+	db       *db.Database
+	verses   *VerseRepository
+	splitter lyrics.Splitter
+	// fuzzyThreshold передается в pg_trgm.word_similarity_threshold
+	fuzzyThreshold float64
+}
+
+// SongRepositoryOptions - необязательные настройки репозитория песен
+type SongRepositoryOptions struct {
+	// Splitter разбивает текст песни на куплеты при создании и обновлении
+	Splitter lyrics.Splitter
+	// FuzzyThreshold - минимальное сходство для нечеткого поиска, от 0 до 1
+	FuzzyThreshold float64
+}
+
+// sortColumn описывает поле сортировки: выражение в SQL, тип для сравнения
+// с курсором и значение поля у уже прочитанной песни
+type sortColumn struct {
+	expr  string
+	cast  string
+	value func(models.Song) string
+}
+
+// songSortColumns - поля, по которым разрешена сортировка списка песен.
+// Необязательные поля сравниваются через COALESCE, чтобы NULL не нарушал порядок
+var songSortColumns = map[string]sortColumn{
+	constants.ColumnTitle: {"s.title", constants.SQLTypeText,
+		func(s models.Song) string { return s.Title }},
+	constants.ColumnArtist: {"s.artist", constants.SQLTypeText,
+		func(s models.Song) string { return s.Artist }},
+	constants.ColumnAlbum: {"COALESCE(s.album, '')", constants.SQLTypeText,
+		func(s models.Song) string { return s.Album }},
+	constants.ColumnReleaseDate: {"COALESCE(s.release_date, DATE '0001-01-01')", constants.SQLTypeDate,
+		func(s models.Song) string {
+			if s.ReleaseDate == "" {
+				return constants.SQLZeroDate
+			}
+			return s.ReleaseDate
+		}},
+	constants.ColumnDuration: {"s.duration", constants.SQLTypeInt,
+		func(s models.Song) string { return strconv.Itoa(s.Duration) }},
+	constants.ColumnCreatedAt: {"s.created_at", constants.SQLTypeTimestamp,
+		func(s models.Song) string { return s.CreatedAt.Format(constants.SQLTimestampFormat) }},
+	constants.ColumnUpdatedAt: {"s.updated_at", constants.SQLTypeTimestamp,
+		func(s models.Song) string { return s.UpdatedAt.Format(constants.SQLTimestampFormat) }},
+}
+
+// IsSongSortField сообщает, можно ли сортировать список песен по полю name
+func IsSongSortField(name string) bool {
+	_, ok := songSortColumns[name]
+	return ok
 }
 
-func NewSongRepository(db *db.Database) (*SongRepository, error) {
+// NewSongRepository создает репозиторий песен. Куплеты, полученные из текста песни,
+// сохраняются через verses
+func NewSongRepository(db *db.Database, verses *VerseRepository, opts SongRepositoryOptions) (*SongRepository, error) {
 	queries, err := loadQueries(songQueries, constants.SongQueriesPath)
 	if err != nil {
 		return nil, err
 	}
+	fragments, err := loadQueries(songQueries, constants.SongFragmentsPath)
+	if err != nil {
+		return nil, err
+	}
+	withFragments(queries, fragments)
 
 	return &SongRepository{
 		BaseRepository: BaseRepository{queries: queries},
 		db:             db,
+		verses:         verses,
+		splitter:       opts.Splitter,
+		fuzzyThreshold: opts.FuzzyThreshold,
 	}, nil
 }
 
 func (r *SongRepository) GetSong(id int) (*models.Song, error) {
-	row := r.db.QueryRow(r.queries[constants.QueryGet], id)
-	song := &models.Song{}
-	err := row.Scan(&song.ID, &song.Title, &song.Artist, &song.Album,
-		&song.Genre, &song.Duration, &song.ReleaseDate,
-		&song.Text, &song.Link)
+	song, err := scanSong(r.db.QueryRow(r.queries[constants.QueryGet], id))
+	if err == sql.ErrNoRows {
+		return nil, ErrSongNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
-	return song, nil
+	return &song, nil
 }
 
+// ListSongs возвращает страницу песен. В курсорном режиме (filter.UseCursor)
+// строки выбираются после курсора по полям сортировки и id, без OFFSET
 func (r *SongRepository) ListSongs(filter models.SongFilter) (*models.PaginatedResponse, error) {
-	offset := (filter.Page - 1) * filter.PerPage
+	if filter.Fuzzy && (filter.Title != "" || filter.Artist != "" || filter.Album != "") {
+		return r.listSongsFuzzy(filter)
+	}
+
+	sort := filter.Sort
+	if len(sort) == 0 {
+		sort = []models.SortField{{Name: constants.DefaultSongSort}}
+	}
 
-	rows, err := r.db.Query(r.queries[constants.QueryListSongs],
-		filter.Title, filter.Artist, filter.Album,
-		filter.Year, filter.Genre,
-		filter.PerPage, offset)
+	after := constants.SQLTrue
+	limit, offset := filter.PerPage, (filter.Page-1)*filter.PerPage
+	args := filterArgs(filter)
+	var afterArgs []any
+
+	if filter.UseCursor {
+		// одна лишняя строка показывает, есть ли следующая страница
+		limit, offset = filter.PerPage+1, 0
+		if filter.Cursor != "" {
+			cursor, err := decodeCursor(filter.Cursor, sortSignature(sort), len(sort))
+			if err != nil {
+				return nil, err
+			}
+			after, afterArgs = keysetCondition(sort, cursor, len(args)+3)
+		}
+	}
+
+	query := strings.Replace(withOrderBy(r.queries[constants.QueryListSongs], sort),
+		constants.SQLAfterPlaceholder, after, 1)
+	rows, err := r.db.Query(query, append(append(args, limit, offset), afterArgs...)...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	songs, err := scanSongs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PaginatedResponse{Data: songs, PerPage: filter.PerPage}
+	if filter.UseCursor {
+		if len(songs) > filter.PerPage {
+			response.Data = songs[:filter.PerPage]
+			response.NextCursor = nextSongCursor(sort, response.Data[filter.PerPage-1])
+		}
+	} else {
+		response.Page = filter.Page
+	}
+
+	if filter.IncludeTotal {
+		var totalCount int
+		if err := r.db.QueryRow(r.queries[constants.QueryCountSongs], args...).Scan(&totalCount); err != nil {
+			return nil, err
+		}
+		setTotal(response, totalCount)
+	}
+
+	return response, nil
+}
+
+// filterArgs возвращает параметры $1-$15 общих условий list.sql, count.sql и list_fuzzy.sql
+func filterArgs(filter models.SongFilter) []any {
+	var genres any
+	if len(filter.Genres) > 0 {
+		genres = pq.Array(filter.Genres)
+	}
+	return []any{
+		filter.Title, filter.Artist, filter.Album, filter.Year, genres,
+		filter.YearFrom, filter.YearTo,
+		nullIfEmpty(filter.ReleasedAfter), nullIfEmpty(filter.ReleasedBefore),
+		filter.DurationMin, filter.DurationMax,
+		filter.HasText, filter.HasLink,
+		filter.CreatedSince, filter.UpdatedSince,
+	}
+}
+
+// nextSongCursor строит курсор, указывающий на песню song
+func nextSongCursor(sort []models.SortField, song models.Song) string {
+	values := make([]string, len(sort))
+	for i, field := range sort {
+		values[i] = songSortColumns[field.Name].value(song)
+	}
+	return encodeCursor(keysetCursor{Sort: sortSignature(sort), Values: values, ID: song.ID})
+}
+
+func setTotal(response *models.PaginatedResponse, totalCount int) {
+	totalPages := totalPages(totalCount, response.PerPage)
+	response.Total = &totalCount
+	response.TotalPages = &totalPages
+}
+
+// listSongsFuzzy ищет песни по сходству триграмм. Порог задается только
+// для текущей транзакции, чтобы не влиять на другие соединения пула
+func (r *SongRepository) listSongsFuzzy(filter models.SongFilter) (*models.PaginatedResponse, error) {
 	var songs []models.Song
 	var totalCount int
+	offset := (filter.Page - 1) * filter.PerPage
 
-	for rows.Next() {
-		var s models.Song
-		var nullText, nullLink, nullAlbum, nullGenre sql.NullString
-		var nullReleaseDate sql.NullTime
-
-		err := rows.Scan(&s.ID, &s.Title, &s.Artist, &nullAlbum,
-			&nullGenre, &s.Duration, &nullReleaseDate,
-			&nullText, &nullLink, &s.CreatedAt, &s.UpdatedAt,
-			&totalCount)
+	variants := make([]string, 3)
+	if filter.Translit {
+		for i, value := range []string{filter.Title, filter.Artist, filter.Album} {
+			variants[i] = translit.Variant(value)
+		}
+	}
+
+	err := withTransaction(r.db, func(tx *sql.Tx) error {
+		threshold := fmt.Sprintf(constants.FuzzyThresholdFormat, r.fuzzyThreshold)
+		if _, err := tx.Exec(r.queries[constants.QuerySetSimilarity], threshold); err != nil {
+			return err
+		}
+
+		args := append(filterArgs(filter), variants[0], variants[1], variants[2], filter.PerPage, offset)
+		rows, err := tx.Query(withOrderBy(r.queries[constants.QueryListSongsFuzzy], filter.Sort, constants.SQLSortSimilarity), args...)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		defer rows.Close()
 
-		s.Text = nullText.String
-		s.Link = nullLink.String
-		s.Album = nullAlbum.String
-		s.Genre = nullGenre.String
-		if nullReleaseDate.Valid {
-			s.ReleaseDate = nullReleaseDate.Time.String()
+		songs, err = scanSongs(rows, &totalCount)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PaginatedResponse{Data: songs, Page: filter.Page, PerPage: filter.PerPage}
+	setTotal(response, totalCount)
+	return response, nil
+}
+
+// withOrderBy подставляет в запрос поля сортировки, затем extra и s.id,
+// чтобы порядок был однозначным и страницы не пересекались
+func withOrderBy(query string, sort []models.SortField, extra ...string) string {
+	if len(sort) == 0 && len(extra) == 0 {
+		sort = []models.SortField{{Name: constants.DefaultSongSort}}
+	}
+
+	terms := make([]string, 0, len(sort)+len(extra)+1)
+	for _, field := range sort {
+		direction := constants.SQLSortAsc
+		if field.Desc {
+			direction = constants.SQLSortDesc
 		}
+		terms = append(terms, songSortColumns[field.Name].expr+direction)
+	}
+	terms = append(terms, extra...)
+	terms = append(terms, constants.SQLSortTiebreaker)
 
+	return strings.Replace(query, constants.SQLOrderByPlaceholder, strings.Join(terms, constants.SQLListSeparator), 1)
+}
+
+// scanSongs читает строки песен, extra - дополнительные колонки после version
+func scanSongs(rows *sql.Rows, extra ...any) ([]models.Song, error) {
+	var songs []models.Song
+	for rows.Next() {
+		s, err := scanSong(rows, extra...)
+		if err != nil {
+			return nil, err
+		}
 		songs = append(songs, s)
 	}
+	return songs, rows.Err()
+}
 
-	totalPages := (totalCount + filter.PerPage - 1) / filter.PerPage
+// SearchSongs ищет песни по тексту и куплетам, результаты упорядочены по релевантности
+func (r *SongRepository) SearchSongs(query string, page, perPage int) (*models.SearchResponse, error) {
+	offset := (page - 1) * perPage
 
-	return &models.PaginatedResponse{
-		Data:       songs,
+	rows, err := r.db.Query(r.queries[constants.QuerySearchSongs], query, perPage, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	var totalCount int
+
+	for rows.Next() {
+		var res models.SearchResult
+		var verseNumber sql.NullInt64
+		if err := rows.Scan(&res.SongID, &res.Title, &res.Artist, &verseNumber,
+			&res.Rank, &res.Snippet, &totalCount); err != nil {
+			return nil, err
+		}
+		if verseNumber.Valid {
+			number := int(verseNumber.Int64)
+			res.VerseNumber = &number
+		}
+		results = append(results, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.SearchResponse{
+		Data:       results,
 		Total:      totalCount,
-		Page:       filter.Page,
-		PerPage:    filter.PerPage,
-		TotalPages: totalPages,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: (totalCount + perPage - 1) / perPage,
 	}, nil
 }
 
-func (r *SongRepository) DeleteSong(id int) error {
-	result, err := r.db.Exec(r.queries[constants.QueryDeleteSong], id)
-	if err != nil {
-		return err
+// rowScanner - общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSong читает колонки песни в порядке запросов get и list,
+// дополнительные колонки после version сканируются в extra
+func scanSong(row rowScanner, extra ...any) (models.Song, error) {
+	var s models.Song
+	var nullText, nullLink, nullAlbum, nullGenre sql.NullString
+	var nullReleaseDate sql.NullTime
+
+	dest := append([]any{&s.ID, &s.Title, &s.Artist, &nullAlbum,
+		&nullGenre, &s.Duration, &nullReleaseDate,
+		&nullText, &nullLink, &s.CreatedAt, &s.UpdatedAt, &s.Version,
+		&s.TrackNumber, &s.DiscNumber}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return s, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
+	s.Text = nullText.String
+	s.Link = nullLink.String
+	s.Album = nullAlbum.String
+	s.Genre = nullGenre.String
+	if nullReleaseDate.Valid {
+		s.ReleaseDate = nullReleaseDate.Time.Format(constants.DateFormat)
+	}
+	return s, nil
+}
+
+// DeleteSong перемещает песню в корзину. Непустой ifMatch содержит допустимые версии
+// песни (If-Match), nil отключает проверку
+func (r *SongRepository) DeleteSong(ctx context.Context, id int, ifMatch []int) error {
+	return r.withAudit(ctx, func(tx *sql.Tx) error {
+		result, err := tx.Exec(r.queries[constants.QueryDeleteSong], id, pq.Array(ifMatch))
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return r.missingOrModified(tx, id)
+		}
+		return nil
+	})
+}
+
+// missingOrModified определяет, почему запрос не затронул строку:
+// песни нет или ее версия не совпала с If-Match
+func (r *SongRepository) missingOrModified(q querier, id int) error {
+	var exists bool
+	if err := q.QueryRow(r.queries[constants.QuerySongExists], id).Scan(&exists); err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+	if exists {
+		return ErrVersionMismatch
 	}
+	return ErrSongNotFound
+}
 
-	return nil
+// UpdateSong обновляет песню и, если текст изменился, пересобирает ее куплеты.
+// Возвращает новую версию песни, ifMatch работает так же, как в DeleteSong
+func (r *SongRepository) UpdateSong(ctx context.Context, id int, songUpdate models.SongUpdate, ifMatch []int) (int, error) {
+	var version int
+	err := r.withAudit(ctx, func(tx *sql.Tx) error {
+		var textChanged bool
+		err := tx.QueryRow(
+			r.queries[constants.QueryUpdateSong],
+			songUpdate.Title,
+			songUpdate.Artist,
+			nullIfEmpty(songUpdate.Album),
+			nullIfEmpty(songUpdate.ReleaseDate),
+			nullIfEmpty(songUpdate.Text),
+			nullIfEmpty(songUpdate.Link),
+			nullIfEmpty(songUpdate.Genre),
+			songUpdate.Duration,
+			id,
+			pq.Array(ifMatch),
+		).Scan(&version, &textChanged)
+		if err == sql.ErrNoRows {
+			return r.missingOrModified(tx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		return r.resyncVerses(tx, id, songUpdate.Text, textChanged)
+	})
+	return version, songWriteError(err)
 }
 
-func (r *SongRepository) UpdateSong(id int, songUpdate models.SongUpdate) error {
-	err := r.db.QueryRow(
-		r.queries[constants.QueryUpdateSong],
-		songUpdate.Title,
-		songUpdate.Artist,
-		songUpdate.Album,
-		songUpdate.ReleaseDate,
-		songUpdate.Text,
-		songUpdate.Link,
-		songUpdate.Genre,
-		songUpdate.Duration,
-		id,
-	).Scan(&id)
+// PatchSong обновляет только переданные в patch поля и возвращает новое состояние песни.
+// Измененный текст пересобирает куплеты, ifMatch работает так же, как в DeleteSong
+func (r *SongRepository) PatchSong(ctx context.Context, id int, patch models.SongPatch, ifMatch []int) (*models.Song, error) {
+	args := []any{id, pq.Array(ifMatch)}
+	var sets []string
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf(constants.SQLAssignFormat, column, len(args)))
+	}
+	setString := func(column string, field models.Optional[string]) {
+		if !field.Set {
+			return
+		}
+		if field.Null {
+			set(column, nil)
+			return
+		}
+		set(column, nullIfEmpty(field.Value))
+	}
 
-	if err == sql.ErrNoRows {
-		return fmt.Errorf(constants.ErrSongNotFound)
+	setString(constants.ColumnTitle, patch.Title)
+	setString(constants.ColumnArtist, patch.Artist)
+	setString(constants.ColumnAlbum, patch.Album)
+	setString(constants.ColumnGenre, patch.Genre)
+	if patch.Duration.Set {
+		set(constants.ColumnDuration, patch.Duration.Value)
+	}
+	setString(constants.ColumnReleaseDate, patch.ReleaseDate)
+	setString(constants.ColumnText, patch.Text)
+	setString(constants.ColumnLink, patch.Link)
+	setInt := func(column string, field models.Optional[int]) {
+		if !field.Set {
+			return
+		}
+		if field.Null {
+			set(column, nil)
+			return
+		}
+		set(column, field.Value)
+	}
+	setInt(constants.ColumnTrackNumber, patch.TrackNumber)
+	setInt(constants.ColumnDiscNumber, patch.DiscNumber)
+
+	if len(sets) == 0 {
+		song, err := r.GetSong(id)
+		if err != nil {
+			return nil, err
+		}
+		if ifMatch != nil && !slices.Contains(ifMatch, song.Version) {
+			return nil, ErrVersionMismatch
+		}
+		return song, nil
+	}
+
+	query := strings.Replace(r.queries[constants.QueryPatchSong],
+		constants.SQLSetPlaceholder, strings.Join(sets, constants.SQLListSeparator), 1)
+
+	var song models.Song
+	err := r.withAudit(ctx, func(tx *sql.Tx) error {
+		var err error
+		var textChanged bool
+		song, err = scanSong(tx.QueryRow(query, args...), &textChanged)
+		if err == sql.ErrNoRows {
+			return r.missingOrModified(tx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		return r.resyncVerses(tx, id, song.Text, textChanged)
+	})
+	if err != nil {
+		return nil, songWriteError(err)
 	}
-	return err
+	return &song, nil
 }
 
-func (r *SongRepository) CreateSimpleSong(input *models.SimpleSongInput) (int, error) {
+// syncVerses сохраняет куплеты новой песни, полученные разбиением текста
+func (r *SongRepository) syncVerses(tx *sql.Tx, songID int, text string) error {
+	blocks := r.splitter.Split(text)
+	if len(blocks) == 0 {
+		return nil
+	}
+	return r.verses.ReplaceVerses(tx, songID, blocks)
+}
+
+// resyncVerses пересобирает куплеты обновленной песни, только если ее текст изменился:
+// иначе сохраняются заданные вручную типы, порядок и временные метки куплетов.
+// Очищенный текст удаляет куплеты, чтобы они не расходились с songs.text
+func (r *SongRepository) resyncVerses(tx *sql.Tx, songID int, text string, textChanged bool) error {
+	if !textChanged {
+		return nil
+	}
+	return r.verses.ReplaceVerses(tx, songID, r.splitter.Split(text))
+}
+
+func (r *SongRepository) CreateSimpleSong(ctx context.Context, input *models.SimpleSongInput) (int, error) {
 	var id int
-	err := r.db.QueryRow(r.queries[constants.QueryCreateSimpleSong],
-		input.Song,
-		input.Group,
-	).Scan(&id)
+	err := r.withAudit(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRow(r.queries[constants.QueryCreateSimpleSong],
+			input.Song,
+			input.Group,
+		).Scan(&id)
+	})
 	return id, err
 }
 
-func (r *SongRepository) CreateSong(song *models.Song) (int, error) {
+// CreateSong сохраняет песню и ее куплеты, полученные разбиением текста
+func (r *SongRepository) CreateSong(ctx context.Context, song *models.Song) (int, error) {
 	var id int
-	err := r.db.QueryRow(
-		r.queries[constants.QueryCreateSong],
-		song.Title,
-		song.Artist,
-		song.Album,
-		song.ReleaseDate,
-		song.Text,
-		song.Link,
-		song.Genre,
-		song.Duration,
-	).Scan(&id)
-	return id, err
+	err := r.withAudit(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			r.queries[constants.QueryCreateSong],
+			song.Title,
+			song.Artist,
+			nullIfEmpty(song.Album),
+			nullIfEmpty(song.ReleaseDate),
+			nullIfEmpty(song.Text),
+			nullIfEmpty(song.Link),
+			nullIfEmpty(song.Genre),
+			song.Duration,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		return r.syncVerses(tx, id, song.Text)
+	})
+	return id, songWriteError(err)
 }
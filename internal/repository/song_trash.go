@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"song-library/internal/constants"
+	"song-library/internal/models"
+)
+
+// ListTrash возвращает страницу песен в корзине, недавно удаленные первыми
+func (r *SongRepository) ListTrash(page, perPage int) (*models.PaginatedResponse, error) {
+	rows, err := r.db.Query(r.queries[constants.QueryTrash], perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := []models.Song{}
+	var totalCount int
+	for rows.Next() {
+		var deletedAt time.Time
+		s, err := scanSong(rows, &deletedAt, &totalCount)
+		if err != nil {
+			return nil, err
+		}
+		s.DeletedAt = &deletedAt
+		songs = append(songs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	response := &models.PaginatedResponse{Data: songs, Page: page, PerPage: perPage}
+	setTotal(response, totalCount)
+	return response, nil
+}
+
+// RestoreSong возвращает песню из корзины вместе с ее куплетами.
+// ErrSongNotInTrash означает, что песни нет в корзине
+func (r *SongRepository) RestoreSong(ctx context.Context, id int) (*models.Song, error) {
+	var song models.Song
+	err := r.withAudit(ctx, func(tx *sql.Tx) error {
+		var err error
+		song, err = scanSong(tx.QueryRow(r.queries[constants.QueryRestoreSong], id))
+		if err == sql.ErrNoRows {
+			return ErrSongNotInTrash
+		}
+		return err
+	})
+	if err != nil {
+		return nil, songWriteError(err)
+	}
+	return &song, nil
+}
+
+// PurgeTrash окончательно удаляет песни, пролежавшие в корзине дольше retention,
+// и возвращает их количество
+func (r *SongRepository) PurgeTrash(retention time.Duration) (int64, error) {
+	result, err := r.db.Exec(r.queries[constants.QueryPurgeTrash], retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
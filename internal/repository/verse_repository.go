@@ -1,10 +1,17 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"embed"
+	"slices"
+
 	"song-library/internal/constants"
 	"song-library/internal/db"
+	"song-library/internal/lyrics"
 	"song-library/internal/models"
+
+	"github.com/lib/pq"
 )
 
 //go:embed queries/verses/*.sql
@@ -38,11 +45,407 @@ func (r *VerseRepository) GetVerses(songID int, page, pageSize int) ([]models.Ve
 
 	var verses []models.Verse
 	for rows.Next() {
-		var v models.Verse
-		if err := rows.Scan(&v.ID, &v.SongID, &v.VerseNumber, &v.Content, &v.CreatedAt); err != nil {
+		v, err := scanVerse(rows)
+		if err != nil {
 			return nil, err
 		}
 		verses = append(verses, v)
 	}
 	return verses, nil
 }
+
+// ListVersesAfter возвращает до limit куплетов после курсора и курсор следующей
+// страницы. Пустой cursor означает первую страницу, пустой следующий - последнюю.
+// Номера куплетов уникальны в песне, поэтому курсор хранит номер вместо id
+func (r *VerseRepository) ListVersesAfter(songID int, cursor string, limit int) ([]models.Verse, string, error) {
+	after := 0
+	if cursor != "" {
+		c, err := decodeCursor(cursor, constants.VerseSortKey, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		after = c.ID
+	}
+
+	rows, err := r.db.Query(r.queries[constants.QueryListVersesAfter], songID, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	verses := []models.Verse{}
+	for rows.Next() {
+		v, err := scanVerse(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		verses = append(verses, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(verses) <= limit {
+		return verses, "", nil
+	}
+	verses = verses[:limit]
+	next := encodeCursor(keysetCursor{Sort: constants.VerseSortKey, ID: verses[limit-1].VerseNumber})
+	return verses, next, nil
+}
+
+// listBySongs возвращает куплеты песен songIDs, сгруппированные по ID песни
+func (r *VerseRepository) listBySongs(tx *sql.Tx, songIDs []int) (map[int][]models.Verse, error) {
+	rows, err := tx.Query(r.queries[constants.QueryListVersesBySongs], pq.Array(songIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	verses := make(map[int][]models.Verse, len(songIDs))
+	for rows.Next() {
+		v, err := scanVerse(rows)
+		if err != nil {
+			return nil, err
+		}
+		verses[v.SongID] = append(verses[v.SongID], v)
+	}
+	return verses, rows.Err()
+}
+
+func (r *VerseRepository) GetVerse(id int) (*models.Verse, error) {
+	v, err := scanVerse(r.db.QueryRow(r.queries[constants.QueryGetVerse], id))
+	if err == sql.ErrNoRows {
+		return nil, ErrVerseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// scanVerse читает колонки куплета в порядке запросов get, get_by_id, list_after и list_by_songs
+func scanVerse(row rowScanner) (models.Verse, error) {
+	var v models.Verse
+	err := row.Scan(&v.ID, &v.SongID, &v.VerseNumber, &v.Type, &v.Content,
+		&v.StartMs, &v.EndMs, &v.CreatedAt, &v.UpdatedAt)
+	return v, err
+}
+
+// CreateVerse вставляет куплет на позицию input.VerseNumber, сдвигая последующие.
+// Если позиция не указана или больше количества куплетов, куплет добавляется в конец
+func (r *VerseRepository) CreateVerse(ctx context.Context, input models.VerseInput) (int, error) {
+	var id int
+	err := r.withAudit(ctx, func(tx *sql.Tx) error {
+		if err := r.lockSong(tx, input.SongID); err != nil {
+			return err
+		}
+		typeID, err := r.verseTypeID(tx, input.Type)
+		if err != nil {
+			return err
+		}
+
+		var next int
+		if err := tx.QueryRow(r.queries[constants.QueryNextVerseNumber], input.SongID).Scan(&next); err != nil {
+			return err
+		}
+		number := input.VerseNumber
+		if number < 1 || number > next {
+			number = next
+		}
+		if number < next {
+			if err := r.shiftVerses(tx, input.SongID, number, 1); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.QueryRow(r.queries[constants.QueryCreateVerse],
+			input.SongID, number, typeID, input.Content, input.StartMs, input.EndMs).Scan(&id); err != nil {
+			return err
+		}
+		if err := r.checkTimings(tx, input.SongID); err != nil {
+			return err
+		}
+		return r.syncSongText(tx, input.SongID)
+	})
+	return id, err
+}
+
+// UpdateVerse меняет тип, текст и временные метки куплета, позиция меняется через MoveVerse
+func (r *VerseRepository) UpdateVerse(ctx context.Context, id int, input models.VerseInput) error {
+	verse, err := r.GetVerse(id)
+	if err != nil {
+		return err
+	}
+
+	return r.withAudit(ctx, func(tx *sql.Tx) error {
+		if err := r.lockSong(tx, verse.SongID); err != nil {
+			return err
+		}
+		typeID, err := r.verseTypeID(tx, input.Type)
+		if err != nil {
+			return err
+		}
+
+		var songID int
+		err = tx.QueryRow(r.queries[constants.QueryUpdateVerse],
+			id, typeID, input.Content, input.StartMs, input.EndMs).Scan(&songID)
+		if err == sql.ErrNoRows {
+			return ErrVerseNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.checkTimings(tx, songID); err != nil {
+			return err
+		}
+		return r.syncSongText(tx, songID)
+	})
+}
+
+// MoveVerse переносит куплет на позицию position, нумерация остается непрерывной
+func (r *VerseRepository) MoveVerse(ctx context.Context, id, position int) (int, error) {
+	verse, err := r.GetVerse(id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.withAudit(ctx, func(tx *sql.Tx) error {
+		if err := r.lockSong(tx, verse.SongID); err != nil {
+			return err
+		}
+
+		ids, err := r.listVerseIDs(tx, verse.SongID)
+		if err != nil {
+			return err
+		}
+
+		current := slices.Index(ids, id)
+		if current == -1 {
+			return ErrVerseNotFound
+		}
+
+		if position > len(ids) {
+			position = len(ids)
+		}
+		ids = slices.Delete(ids, current, current+1)
+		ids = slices.Insert(ids, position-1, id)
+
+		if _, err := tx.Exec(r.queries[constants.QueryReorderVerses], verse.SongID, pq.Array(ids)); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(r.queries[constants.QueryUnstashVerses], verse.SongID); err != nil {
+			return err
+		}
+		if err := r.checkTimings(tx, verse.SongID); err != nil {
+			return err
+		}
+		return r.syncSongText(tx, verse.SongID)
+	})
+	return position, err
+}
+
+// DeleteVerse удаляет куплет и закрывает образовавшийся разрыв в нумерации
+func (r *VerseRepository) DeleteVerse(ctx context.Context, id int) error {
+	verse, err := r.GetVerse(id)
+	if err != nil {
+		return err
+	}
+
+	return r.withAudit(ctx, func(tx *sql.Tx) error {
+		if err := r.lockSong(tx, verse.SongID); err != nil {
+			return err
+		}
+
+		var songID, number int
+		err := tx.QueryRow(r.queries[constants.QueryDeleteVerse], id).Scan(&songID, &number)
+		if err == sql.ErrNoRows {
+			return ErrVerseNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := r.shiftVerses(tx, songID, number+1, -1); err != nil {
+			return err
+		}
+		return r.syncSongText(tx, songID)
+	})
+}
+
+// ReplaceVerses заменяет все куплеты песни блоками текста в рамках транзакции tx
+func (r *VerseRepository) ReplaceVerses(tx *sql.Tx, songID int, blocks []lyrics.Block) error {
+	if _, err := tx.Exec(r.queries[constants.QueryDeleteSongVerses], songID); err != nil {
+		return err
+	}
+
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	typeIDs := make(map[string]int)
+	types := make([]int64, len(blocks))
+	contents := make([]string, len(blocks))
+	starts := make([]sql.NullInt64, len(blocks))
+	ends := make([]sql.NullInt64, len(blocks))
+	for i, block := range blocks {
+		typeID, ok := typeIDs[block.Type]
+		if !ok {
+			var err error
+			if typeID, err = r.verseTypeID(tx, block.Type); err != nil {
+				return err
+			}
+			typeIDs[block.Type] = typeID
+		}
+		types[i] = int64(typeID)
+		contents[i] = block.Content
+		starts[i] = nullInt(block.StartMs)
+		ends[i] = nullInt(block.EndMs)
+	}
+
+	_, err := tx.Exec(r.queries[constants.QueryCreateVerses],
+		songID, pq.Array(types), pq.Array(contents), pq.Array(starts), pq.Array(ends))
+	return err
+}
+
+func nullInt(value *int) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*value), Valid: true}
+}
+
+// GetLyrics возвращает все куплеты песни по порядку и длительность песни в секундах
+func (r *VerseRepository) GetLyrics(songID int) ([]lyrics.Block, int, error) {
+	var blocks []lyrics.Block
+	var duration int
+	err := withTransaction(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(r.queries[constants.QueryGetSongDuration], songID).Scan(&duration)
+		if err == sql.ErrNoRows {
+			return ErrSongNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		verses, err := r.listBySongs(tx, []int{songID})
+		if err != nil {
+			return err
+		}
+		for _, v := range verses[songID] {
+			blocks = append(blocks, lyrics.Block{Type: v.Type, Content: v.Content, StartMs: v.StartMs, EndMs: v.EndMs})
+		}
+		return nil
+	})
+	return blocks, duration, err
+}
+
+// ReplaceLyrics заменяет куплеты песни синхронизированным текстом и обновляет
+// по ним текст песни, метки проверяются по длительности песни до изменения куплетов
+func (r *VerseRepository) ReplaceLyrics(ctx context.Context, songID int, blocks []lyrics.Block) error {
+	return r.withAudit(ctx, func(tx *sql.Tx) error {
+		if err := r.lockSong(tx, songID); err != nil {
+			return err
+		}
+
+		var duration int
+		if err := tx.QueryRow(r.queries[constants.QueryGetSongDuration], songID).Scan(&duration); err != nil {
+			return err
+		}
+		if err := lyrics.ValidateTimings(blocks, duration*1000); err != nil {
+			return err
+		}
+		if err := r.ReplaceVerses(tx, songID, blocks); err != nil {
+			return err
+		}
+		return r.syncSongText(tx, songID)
+	})
+}
+
+// checkTimings проверяет временные метки всех куплетов песни после изменения
+func (r *VerseRepository) checkTimings(tx *sql.Tx, songID int) error {
+	rows, err := tx.Query(r.queries[constants.QueryListTimings], songID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var blocks []lyrics.Block
+	var duration int
+	for rows.Next() {
+		var b lyrics.Block
+		if err := rows.Scan(&b.StartMs, &b.EndMs, &duration); err != nil {
+			return err
+		}
+		blocks = append(blocks, b)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return lyrics.ValidateTimings(blocks, duration*1000)
+}
+
+// withAudit выполняет fn в транзакции, в которой автор и ID запроса из ctx
+// попадают в ревизию песни, созданную при обновлении ее текста
+func (r *VerseRepository) withAudit(ctx context.Context, fn func(*sql.Tx) error) error {
+	return withTransaction(r.db, func(tx *sql.Tx) error {
+		if err := setAudit(ctx, tx); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// syncSongText пересобирает songs.text из куплетов после их изменения, чтобы
+// следующее обновление песни с прежним текстом не откатило правки куплетов
+func (r *VerseRepository) syncSongText(tx *sql.Tx, songID int) error {
+	_, err := tx.Exec(r.queries[constants.QuerySyncSongText], songID, constants.VerseSeparator)
+	return err
+}
+
+func (r *VerseRepository) lockSong(tx *sql.Tx, songID int) error {
+	err := tx.QueryRow(r.queries[constants.QueryLockSong], songID).Scan(&songID)
+	if err == sql.ErrNoRows {
+		return ErrSongNotFound
+	}
+	return err
+}
+
+func (r *VerseRepository) verseTypeID(tx *sql.Tx, name string) (int, error) {
+	if name == "" {
+		name = constants.DefaultVerseType
+	}
+
+	var id int
+	err := tx.QueryRow(r.queries[constants.QueryVerseTypeID], name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, ErrUnknownVerseType
+	}
+	return id, err
+}
+
+func (r *VerseRepository) listVerseIDs(tx *sql.Tx, songID int) ([]int, error) {
+	rows, err := tx.Query(r.queries[constants.QueryListVerseIDs], songID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// shiftVerses сдвигает номера куплетов начиная с from на delta в два шага
+func (r *VerseRepository) shiftVerses(tx *sql.Tx, songID, from, delta int) error {
+	if _, err := tx.Exec(r.queries[constants.QueryShiftVerses], songID, from, delta); err != nil {
+		return err
+	}
+	_, err := tx.Exec(r.queries[constants.QueryUnstashVerses], songID)
+	return err
+}
@@ -1,28 +1,161 @@
 package routers
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
+
+	_ "song-library/docs" // автоматически сгенерированная документация
+	"song-library/internal/auth"
 	"song-library/internal/constants"
-	"song-library/internal/handlers"
+	"song-library/internal/i18n"
 	"song-library/internal/logger"
 	"song-library/internal/middleware"
-
-	_ "song-library/docs" // автоматически сгенерированная документация
+	"song-library/internal/problem"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func SetupRoutes(songHandler *handlers.SongHandler, verseHandler *handlers.VerseHandler) http.Handler {
+// SongRoutes - обработчики маршрутов песен, реализуются handlers.SongHandler
+type SongRoutes interface {
+	GetSongs(w http.ResponseWriter, r *http.Request)
+	CreateSong(w http.ResponseWriter, r *http.Request)
+	ExportSongs(w http.ResponseWriter, r *http.Request)
+	GetDuplicates(w http.ResponseWriter, r *http.Request)
+	MergeSong(w http.ResponseWriter, r *http.Request)
+	GetTrash(w http.ResponseWriter, r *http.Request)
+	RestoreSong(w http.ResponseWriter, r *http.Request)
+	GetSongHistory(w http.ResponseWriter, r *http.Request)
+	GetRevisionDiff(w http.ResponseWriter, r *http.Request)
+	RevertSong(w http.ResponseWriter, r *http.Request)
+	GetSongInfo(w http.ResponseWriter, r *http.Request)
+	GetSong(w http.ResponseWriter, r *http.Request)
+	UpdateSong(w http.ResponseWriter, r *http.Request)
+	PatchSong(w http.ResponseWriter, r *http.Request)
+	DeleteSong(w http.ResponseWriter, r *http.Request)
+	SearchSongs(w http.ResponseWriter, r *http.Request)
+}
+
+// VerseRoutes - обработчики маршрутов куплетов, реализуются handlers.VerseHandler
+type VerseRoutes interface {
+	GetVerses(w http.ResponseWriter, r *http.Request)
+	CreateVerse(w http.ResponseWriter, r *http.Request)
+	UpdateVerse(w http.ResponseWriter, r *http.Request)
+	DeleteVerse(w http.ResponseWriter, r *http.Request)
+	MoveVerse(w http.ResponseWriter, r *http.Request)
+	GetLyrics(w http.ResponseWriter, r *http.Request)
+	UploadLyrics(w http.ResponseWriter, r *http.Request)
+}
+
+// CatalogRoutes - обработчики справочников, реализуются handlers.CatalogHandler
+type CatalogRoutes interface {
+	GetArtists(w http.ResponseWriter, r *http.Request)
+	GetArtist(w http.ResponseWriter, r *http.Request)
+	GetArtistSongs(w http.ResponseWriter, r *http.Request)
+	GetAlbums(w http.ResponseWriter, r *http.Request)
+	GetAlbum(w http.ResponseWriter, r *http.Request)
+	UpdateAlbum(w http.ResponseWriter, r *http.Request)
+	GetAlbumSongs(w http.ResponseWriter, r *http.Request)
+	GetGenres(w http.ResponseWriter, r *http.Request)
+	GetGenreSongs(w http.ResponseWriter, r *http.Request)
+	GetSongArtists(w http.ResponseWriter, r *http.Request)
+	SetSongArtists(w http.ResponseWriter, r *http.Request)
+}
+
+// ImportRoutes - обработчик импорта, реализуется handlers.ImportHandler
+type ImportRoutes interface {
+	ImportSongs(w http.ResponseWriter, r *http.Request)
+}
+
+// PlaylistRoutes - обработчики плейлистов, реализуются handlers.PlaylistHandler
+type PlaylistRoutes interface {
+	GetPlaylists(w http.ResponseWriter, r *http.Request)
+	CreatePlaylist(w http.ResponseWriter, r *http.Request)
+	GetPlaylist(w http.ResponseWriter, r *http.Request)
+	UpdatePlaylist(w http.ResponseWriter, r *http.Request)
+	DeletePlaylist(w http.ResponseWriter, r *http.Request)
+	GetItems(w http.ResponseWriter, r *http.Request)
+	AddItem(w http.ResponseWriter, r *http.Request)
+	ReorderItems(w http.ResponseWriter, r *http.Request)
+	RemoveItem(w http.ResponseWriter, r *http.Request)
+	MoveItem(w http.ResponseWriter, r *http.Request)
+}
+
+func SetupRoutes(songHandler SongRoutes, verseHandler VerseRoutes,
+	catalogHandler CatalogRoutes, importHandler ImportRoutes,
+	playlistHandler PlaylistRoutes, authMiddleware func(http.Handler) http.Handler,
+	policy *auth.Policy, limits *middleware.RateLimits, errLogger *i18n.Logger) http.Handler {
 	router := http.NewServeMux()
+	access := routePolicy{policy: policy, limits: limits, logger: errLogger}
+
+	// Маршруты песен
+	handle(router, access, http.MethodGet, constants.APISongsPath, songHandler.GetSongs)
+	handle(router, access, http.MethodPost, constants.APISongsPath, songHandler.CreateSong)
+	handle(router, access, http.MethodPost, constants.APISongImportPath, importHandler.ImportSongs)
+	handle(router, access, http.MethodGet, constants.APISongExportPath, songHandler.ExportSongs)
+	handle(router, access, http.MethodGet, constants.APISongDuplicatesPath, songHandler.GetDuplicates)
+	handle(router, access, http.MethodPost, constants.APISongMergePath, songHandler.MergeSong)
+	handle(router, access, http.MethodGet, constants.APISongTrashPath, songHandler.GetTrash)
+	handle(router, access, http.MethodPost, constants.APISongRestorePath, songHandler.RestoreSong)
+	handle(router, access, http.MethodGet, constants.APISongHistoryPath, songHandler.GetSongHistory)
+	handle(router, access, http.MethodGet, constants.APISongHistoryDiff, songHandler.GetRevisionDiff)
+	handle(router, access, http.MethodPost, constants.APISongRevertPath, songHandler.RevertSong)
+	handle(router, access, http.MethodGet, constants.APISongInfo, songHandler.GetSongInfo)
+	handle(router, access, http.MethodGet, constants.APISongPath, songHandler.GetSong)
+	handle(router, access, http.MethodPut, constants.APISongPath, songHandler.UpdateSong)
+	handle(router, access, http.MethodPatch, constants.APISongPath, songHandler.PatchSong)
+	handle(router, access, http.MethodDelete, constants.APISongPath, songHandler.DeleteSong)
+
+	// Поиск
+	handle(router, access, http.MethodGet, constants.APISearchPath, songHandler.SearchSongs)
+
+	// Маршруты куплетов
+	handle(router, access, http.MethodGet, constants.APISongVersesPath, verseHandler.GetVerses)
+	handle(router, access, http.MethodPost, constants.APISongVersesPath, verseHandler.CreateVerse)
+	handle(router, access, http.MethodPut, constants.APIVersePath, verseHandler.UpdateVerse)
+	handle(router, access, http.MethodDelete, constants.APIVersePath, verseHandler.DeleteVerse)
+	handle(router, access, http.MethodPut, constants.APIVersePositionPath, verseHandler.MoveVerse)
+	handle(router, access, http.MethodGet, constants.APISongLyricsPath, verseHandler.GetLyrics)
+	handle(router, access, http.MethodPut, constants.APISongLyricsPath, verseHandler.UploadLyrics)
+
+	// Справочники исполнителей, альбомов и жанров
+	handle(router, access, http.MethodGet, constants.APIArtistsPath, catalogHandler.GetArtists)
+	handle(router, access, http.MethodGet, constants.APIArtistPath, catalogHandler.GetArtist)
+	handle(router, access, http.MethodGet, constants.APIArtistSongsPath, catalogHandler.GetArtistSongs)
+	handle(router, access, http.MethodGet, constants.APIAlbumsPath, catalogHandler.GetAlbums)
+	handle(router, access, http.MethodGet, constants.APIAlbumPath, catalogHandler.GetAlbum)
+	handle(router, access, http.MethodPut, constants.APIAlbumPath, catalogHandler.UpdateAlbum)
+	handle(router, access, http.MethodGet, constants.APIAlbumSongsPath, catalogHandler.GetAlbumSongs)
+	handle(router, access, http.MethodGet, constants.APIGenresPath, catalogHandler.GetGenres)
+	handle(router, access, http.MethodGet, constants.APIGenreSongsPath, catalogHandler.GetGenreSongs)
+	handle(router, access, http.MethodGet, constants.APISongArtistsPath, catalogHandler.GetSongArtists)
+	handle(router, access, http.MethodPut, constants.APISongArtistsPath, catalogHandler.SetSongArtists)
+
+	// Плейлисты
+	handle(router, access, http.MethodGet, constants.APIPlaylistsPath, playlistHandler.GetPlaylists)
+	handle(router, access, http.MethodPost, constants.APIPlaylistsPath, playlistHandler.CreatePlaylist)
+	handle(router, access, http.MethodGet, constants.APIPlaylistPath, playlistHandler.GetPlaylist)
+	handle(router, access, http.MethodPut, constants.APIPlaylistPath, playlistHandler.UpdatePlaylist)
+	handle(router, access, http.MethodDelete, constants.APIPlaylistPath, playlistHandler.DeletePlaylist)
+	handle(router, access, http.MethodGet, constants.APIPlaylistItemsPath, playlistHandler.GetItems)
+	handle(router, access, http.MethodPost, constants.APIPlaylistItemsPath, playlistHandler.AddItem)
+	handle(router, access, http.MethodPut, constants.APIPlaylistItemsPath, playlistHandler.ReorderItems)
+	handle(router, access, http.MethodDelete, constants.APIPlaylistItemPath, playlistHandler.RemoveItem)
+	handle(router, access, http.MethodPut, constants.APIPlaylistItemPositionPath, playlistHandler.MoveItem)
+
+	// Устаревшие маршруты, будут удалены в следующем релизе
+	deprecated(router, access, http.MethodDelete, constants.APISongDelete, constants.APISongPath, songHandler.DeleteSong)
+	deprecated(router, access, http.MethodPut, constants.APISongUpdate, constants.APISongPath, songHandler.UpdateSong)
+	deprecated(router, access, http.MethodPost, constants.APISongCreate, constants.APISongsPath, songHandler.CreateSong)
+	deprecated(router, access, http.MethodGet, constants.APIVersesPath, constants.APISongVersesPath, verseHandler.GetVerses)
+	deprecated(router, access, http.MethodPost, constants.APIVerseCreate, constants.APISongVersesPath, verseHandler.CreateVerse)
+	deprecated(router, access, http.MethodPut, constants.APIVerseUpdate, constants.APIVersePath, verseHandler.UpdateVerse)
+	deprecated(router, access, http.MethodPut, constants.APIVerseMove, constants.APIVersePositionPath, verseHandler.MoveVerse)
+	deprecated(router, access, http.MethodDelete, constants.APIVerseDelete, constants.APIVersePath, verseHandler.DeleteVerse)
+
+	router.Handle(constants.APINotFoundPath, notFound(router))
 
-	// Добавляем маршруты
-	router.HandleFunc(constants.APISongsPath, songHandler.GetSongs)
-	router.HandleFunc(constants.APISongDelete, songHandler.DeleteSong)
-	router.HandleFunc(constants.APISongUpdate, songHandler.UpdateSong)
-	router.HandleFunc(constants.APISongCreate, songHandler.CreateSong)
-	router.HandleFunc(constants.APISongInfo, songHandler.GetSongInfo)
-	router.HandleFunc(constants.APIVersesPath, verseHandler.GetVerses)
 	router.Handle(constants.MetricsPath, promhttp.Handler())
 
 	// Swagger
@@ -32,9 +165,69 @@ func SetupRoutes(songHandler *handlers.SongHandler, verseHandler *handlers.Verse
 
 	// Пприменяем middleware
 	logger := logger.NewLogger()
-	handler := middleware.RequestLogger(logger)(
-		middleware.MetricsMiddleware(router),
-	)
+	handler := middleware.RequestID(middleware.RequestLogger(logger)(
+		middleware.MetricsMiddleware(middleware.Language(limits.ByIP(authMiddleware(router)))),
+	))
 
 	return handler
 }
+
+// route формирует шаблон маршрута с методом, например "GET /api/songs/{id}"
+func route(method, path string) string {
+	return fmt.Sprintf(constants.RouteFormat, method, path)
+}
+
+// routeMethods - методы, которые проверяются для ответа 405
+var routeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// notFound отвечает на запросы к API, не совпавшие ни с одним маршрутом. Из-за этого
+// обработчика http.ServeMux не отвечает 405 сам, поэтому методы пути перебираются
+// здесь: если путь есть с другим методом - 405 с заголовком Allow, иначе 404
+func notFound(router *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range routeMethods {
+			probe := r.Clone(r.Context())
+			probe.Method = method
+			if _, pattern := router.Handler(probe); pattern != constants.APINotFoundPath {
+				allowed = append(allowed, method)
+				if method == http.MethodGet {
+					allowed = append(allowed, http.MethodHead)
+				}
+			}
+		}
+
+		if len(allowed) == 0 {
+			problem.Error(w, constants.CodeNotFound, constants.ErrRouteNotFound, http.StatusNotFound)
+			return
+		}
+		w.Header().Set(constants.HeaderAllow, strings.Join(allowed, ", "))
+		problem.Error(w, constants.CodeMethodNotAllowed, constants.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
+	})
+}
+
+// routePolicy ограничивает частоту запросов к маршруту и проверяет роль клиента
+// по политике доступа
+type routePolicy struct {
+	policy *auth.Policy
+	limits *middleware.RateLimits
+	logger *i18n.Logger
+}
+
+func (p routePolicy) authorize(method, path string) func(http.Handler) http.Handler {
+	limit := p.limits.For(method, path)
+	authorize := middleware.Authorize(p.policy.Role(method, path), p.logger)
+	return func(next http.Handler) http.Handler {
+		return limit(authorize(next))
+	}
+}
+
+// handle регистрирует маршрут с ограничением частоты запросов и проверкой роли клиента
+func handle(router *http.ServeMux, policy routePolicy, method, path string, handler http.HandlerFunc) {
+	router.Handle(route(method, path), policy.authorize(method, path)(handler))
+}
+
+// deprecated регистрирует устаревший маршрут, указывая в ответе на successor
+func deprecated(router *http.ServeMux, policy routePolicy, method, path, successor string, handler http.HandlerFunc) {
+	router.Handle(route(method, path), policy.authorize(method, path)(middleware.Deprecated(successor)(handler)))
+}
@@ -0,0 +1,361 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"song-library/internal/auth"
+	"song-library/internal/config"
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/middleware"
+	"song-library/internal/models"
+	"song-library/internal/repository"
+)
+
+// stubHandler отвечает 200 на любой маршрут, чтобы проверять только доступ
+type stubHandler struct{}
+
+func (stubHandler) ok(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) }
+
+func (h stubHandler) GetSongs(w http.ResponseWriter, _ *http.Request)        { h.ok(w) }
+func (h stubHandler) CreateSong(w http.ResponseWriter, _ *http.Request)      { h.ok(w) }
+func (h stubHandler) ExportSongs(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) GetDuplicates(w http.ResponseWriter, _ *http.Request)   { h.ok(w) }
+func (h stubHandler) MergeSong(w http.ResponseWriter, _ *http.Request)       { h.ok(w) }
+func (h stubHandler) GetTrash(w http.ResponseWriter, _ *http.Request)        { h.ok(w) }
+func (h stubHandler) RestoreSong(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) GetSongHistory(w http.ResponseWriter, _ *http.Request)  { h.ok(w) }
+func (h stubHandler) GetRevisionDiff(w http.ResponseWriter, _ *http.Request) { h.ok(w) }
+func (h stubHandler) RevertSong(w http.ResponseWriter, _ *http.Request)      { h.ok(w) }
+func (h stubHandler) GetSongInfo(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) GetSong(w http.ResponseWriter, _ *http.Request)         { h.ok(w) }
+func (h stubHandler) UpdateSong(w http.ResponseWriter, _ *http.Request)      { h.ok(w) }
+func (h stubHandler) PatchSong(w http.ResponseWriter, _ *http.Request)       { h.ok(w) }
+func (h stubHandler) DeleteSong(w http.ResponseWriter, _ *http.Request)      { h.ok(w) }
+func (h stubHandler) SearchSongs(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) GetVerses(w http.ResponseWriter, _ *http.Request)       { h.ok(w) }
+func (h stubHandler) CreateVerse(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) UpdateVerse(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) DeleteVerse(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) MoveVerse(w http.ResponseWriter, _ *http.Request)       { h.ok(w) }
+func (h stubHandler) GetLyrics(w http.ResponseWriter, _ *http.Request)       { h.ok(w) }
+func (h stubHandler) UploadLyrics(w http.ResponseWriter, _ *http.Request)    { h.ok(w) }
+func (h stubHandler) GetArtists(w http.ResponseWriter, _ *http.Request)      { h.ok(w) }
+func (h stubHandler) GetArtist(w http.ResponseWriter, _ *http.Request)       { h.ok(w) }
+func (h stubHandler) GetArtistSongs(w http.ResponseWriter, _ *http.Request)  { h.ok(w) }
+func (h stubHandler) GetAlbums(w http.ResponseWriter, _ *http.Request)       { h.ok(w) }
+func (h stubHandler) GetAlbum(w http.ResponseWriter, _ *http.Request)        { h.ok(w) }
+func (h stubHandler) UpdateAlbum(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) GetAlbumSongs(w http.ResponseWriter, _ *http.Request)   { h.ok(w) }
+func (h stubHandler) GetGenres(w http.ResponseWriter, _ *http.Request)       { h.ok(w) }
+func (h stubHandler) GetGenreSongs(w http.ResponseWriter, _ *http.Request)   { h.ok(w) }
+func (h stubHandler) GetSongArtists(w http.ResponseWriter, _ *http.Request)  { h.ok(w) }
+func (h stubHandler) SetSongArtists(w http.ResponseWriter, _ *http.Request)  { h.ok(w) }
+func (h stubHandler) ImportSongs(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) GetPlaylists(w http.ResponseWriter, _ *http.Request)    { h.ok(w) }
+func (h stubHandler) CreatePlaylist(w http.ResponseWriter, _ *http.Request)  { h.ok(w) }
+func (h stubHandler) GetPlaylist(w http.ResponseWriter, _ *http.Request)     { h.ok(w) }
+func (h stubHandler) UpdatePlaylist(w http.ResponseWriter, _ *http.Request)  { h.ok(w) }
+func (h stubHandler) DeletePlaylist(w http.ResponseWriter, _ *http.Request)  { h.ok(w) }
+func (h stubHandler) GetItems(w http.ResponseWriter, _ *http.Request)        { h.ok(w) }
+func (h stubHandler) AddItem(w http.ResponseWriter, _ *http.Request)         { h.ok(w) }
+func (h stubHandler) ReorderItems(w http.ResponseWriter, _ *http.Request)    { h.ok(w) }
+func (h stubHandler) RemoveItem(w http.ResponseWriter, _ *http.Request)      { h.ok(w) }
+func (h stubHandler) MoveItem(w http.ResponseWriter, _ *http.Request)        { h.ok(w) }
+
+// fakeKeyStore хранит API-ключи в памяти по хешу
+type fakeKeyStore map[string]*models.APIKey
+
+func (s fakeKeyStore) FindAPIKey(hash string) (*models.APIKey, error) {
+	if key, ok := s[hash]; ok {
+		return key, nil
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+const anonymous = "anonymous"
+
+// routeCase - маршрут с ролью, которую он требует по политике по умолчанию
+type routeCase struct {
+	method string
+	path   string
+	role   string
+}
+
+// routeCases перечисляет все маршруты API. Ожидаемые роли записаны явно,
+// а не берутся из Policy, чтобы тест ловил случайные изменения политики
+var routeCases = []routeCase{
+	{http.MethodGet, "/api/songs", constants.RoleViewer},
+	{http.MethodPost, "/api/songs", constants.RoleEditor},
+	{http.MethodPost, "/api/songs/import", constants.RoleAdmin},
+	{http.MethodGet, "/api/songs/export", constants.RoleViewer},
+	{http.MethodGet, "/api/songs/duplicates", constants.RoleViewer},
+	{http.MethodPost, "/api/songs/1/merge", constants.RoleAdmin},
+	{http.MethodGet, "/api/songs/trash", constants.RoleViewer},
+	{http.MethodPost, "/api/songs/1/restore", constants.RoleEditor},
+	{http.MethodGet, "/api/songs/1/history", constants.RoleViewer},
+	{http.MethodGet, "/api/songs/1/history/diff", constants.RoleViewer},
+	{http.MethodPost, "/api/songs/1/revert/2", constants.RoleEditor},
+	{http.MethodGet, "/api/songs/info", constants.RoleViewer},
+	{http.MethodGet, "/api/songs/1", constants.RoleViewer},
+	{http.MethodPut, "/api/songs/1", constants.RoleEditor},
+	{http.MethodPatch, "/api/songs/1", constants.RoleEditor},
+	{http.MethodDelete, "/api/songs/1", constants.RoleAdmin},
+	{http.MethodGet, "/api/search", constants.RoleViewer},
+	{http.MethodGet, "/api/songs/1/verses", constants.RoleViewer},
+	{http.MethodPost, "/api/songs/1/verses", constants.RoleEditor},
+	{http.MethodPut, "/api/verses/1", constants.RoleEditor},
+	{http.MethodDelete, "/api/verses/1", constants.RoleAdmin},
+	{http.MethodPut, "/api/verses/1/position", constants.RoleEditor},
+	{http.MethodGet, "/api/songs/1/lyrics", constants.RoleViewer},
+	{http.MethodPut, "/api/songs/1/lyrics", constants.RoleEditor},
+	{http.MethodGet, "/api/artists", constants.RoleViewer},
+	{http.MethodGet, "/api/artists/1", constants.RoleViewer},
+	{http.MethodGet, "/api/artists/1/songs", constants.RoleViewer},
+	{http.MethodGet, "/api/albums", constants.RoleViewer},
+	{http.MethodGet, "/api/albums/1", constants.RoleViewer},
+	{http.MethodPut, "/api/albums/1", constants.RoleEditor},
+	{http.MethodGet, "/api/albums/1/songs", constants.RoleViewer},
+	{http.MethodGet, "/api/genres", constants.RoleViewer},
+	{http.MethodGet, "/api/genres/1/songs", constants.RoleViewer},
+	{http.MethodGet, "/api/songs/1/artists", constants.RoleViewer},
+	{http.MethodPut, "/api/songs/1/artists", constants.RoleEditor},
+	{http.MethodGet, "/api/playlists", constants.RoleViewer},
+	{http.MethodPost, "/api/playlists", constants.RoleEditor},
+	{http.MethodGet, "/api/playlists/1", constants.RoleViewer},
+	{http.MethodPut, "/api/playlists/1", constants.RoleEditor},
+	{http.MethodDelete, "/api/playlists/1", constants.RoleAdmin},
+	{http.MethodGet, "/api/playlists/1/items", constants.RoleViewer},
+	{http.MethodPost, "/api/playlists/1/items", constants.RoleEditor},
+	{http.MethodPut, "/api/playlists/1/items", constants.RoleEditor},
+	{http.MethodDelete, "/api/playlists/1/items/2", constants.RoleEditor},
+	{http.MethodPut, "/api/playlists/1/items/2/position", constants.RoleEditor},
+	{http.MethodDelete, "/api/songs/delete", constants.RoleAdmin},
+	{http.MethodPut, "/api/songs/update", constants.RoleEditor},
+	{http.MethodPost, "/api/songs/create", constants.RoleEditor},
+	{http.MethodGet, "/api/verses", constants.RoleViewer},
+	{http.MethodPost, "/api/verses/create", constants.RoleEditor},
+	{http.MethodPut, "/api/verses/update", constants.RoleEditor},
+	{http.MethodPut, "/api/verses/move", constants.RoleEditor},
+	{http.MethodDelete, "/api/verses/delete", constants.RoleAdmin},
+}
+
+// testServer собирает маршруты с заглушками обработчиков и возвращает их
+// вместе с API-ключами ролей viewer, editor и admin
+func testServer(t *testing.T, policy *auth.Policy, publicRead bool) (http.Handler, map[string]string) {
+	t.Helper()
+	handler, keys, _ := testServerLimits(t, policy, publicRead, config.RateLimitConfig{})
+	return handler, keys
+}
+
+// testServerLimits собирает маршруты как testServer, но с ограничителями частоты по cfg
+func testServerLimits(t *testing.T, policy *auth.Policy, publicRead bool, cfg config.RateLimitConfig) (http.Handler, map[string]string, *middleware.RateLimits) {
+	t.Helper()
+	logger := i18n.NewLogger(log.New(io.Discard, "", 0))
+
+	store := fakeKeyStore{}
+	keys := make(map[string]string)
+	for _, role := range []string{constants.RoleViewer, constants.RoleEditor, constants.RoleAdmin} {
+		key, hash, _, err := auth.NewAPIKey()
+		if err != nil {
+			t.Fatalf("NewAPIKey() error = %v", err)
+		}
+		store[hash] = &models.APIKey{Name: role, Role: role}
+		keys[role] = key
+	}
+
+	authMiddleware := middleware.Auth(auth.NewAuthenticator(store, nil), publicRead, logger)
+	stub := stubHandler{}
+	limits := middleware.NewRateLimits(cfg, logger)
+	handler := SetupRoutes(stub, stub, stub, stub, stub, authMiddleware, policy, limits, logger)
+	return handler, keys, limits
+}
+
+// expectedStatus возвращает ответ клиента role на маршрут, требующий required
+func expectedStatus(role, required string, publicRead bool, method string) int {
+	rank := map[string]int{constants.RoleViewer: 1, constants.RoleEditor: 2, constants.RoleAdmin: 3}
+	switch {
+	case role == anonymous && publicRead && required == constants.RoleViewer &&
+		(method == http.MethodGet || method == http.MethodHead):
+		return http.StatusOK
+	case role == anonymous:
+		return http.StatusUnauthorized
+	case rank[role] < rank[required]:
+		return http.StatusForbidden
+	}
+	return http.StatusOK
+}
+
+func serve(handler http.Handler, method, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if key != "" {
+		req.Header.Set(constants.HeaderAPIKey, key)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func checkAccess(t *testing.T, handler http.Handler, keys map[string]string, publicRead bool, cases []routeCase) {
+	t.Helper()
+	for _, tc := range cases {
+		for _, role := range []string{anonymous, constants.RoleViewer, constants.RoleEditor, constants.RoleAdmin} {
+			t.Run(tc.method+" "+tc.path+" "+role, func(t *testing.T) {
+				rec := serve(handler, tc.method, tc.path, keys[role])
+				if want := expectedStatus(role, tc.role, publicRead, tc.method); rec.Code != want {
+					t.Errorf("status = %d, want %d", rec.Code, want)
+				}
+			})
+		}
+	}
+}
+
+func TestRouteAccessDefaultPolicy(t *testing.T) {
+	for _, publicRead := range []bool{true, false} {
+		handler, keys := testServer(t, auth.DefaultPolicy(), publicRead)
+		checkAccess(t, handler, keys, publicRead, routeCases)
+	}
+}
+
+// Правила из файла политики переопределяют роли по умолчанию, остальные
+// маршруты сохраняют роли DefaultPolicy
+func TestRouteAccessPolicyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	policyFile := `{
+		"methods": {"delete": "editor"},
+		"routes": {
+			"POST /api/songs/import": "editor",
+			"GET /api/songs/trash": "editor",
+			"DELETE /api/playlists/{id}/items/{item}": "admin"
+		}
+	}`
+	if err := os.WriteFile(path, []byte(policyFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := auth.LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy() error = %v", err)
+	}
+
+	overrides := map[string]string{
+		"DELETE /api/songs/1":             constants.RoleEditor,
+		"DELETE /api/verses/1":            constants.RoleEditor,
+		"DELETE /api/playlists/1":         constants.RoleEditor,
+		"DELETE /api/songs/delete":        constants.RoleEditor,
+		"DELETE /api/verses/delete":       constants.RoleEditor,
+		"POST /api/songs/import":          constants.RoleEditor,
+		"GET /api/songs/trash":            constants.RoleEditor,
+		"DELETE /api/playlists/1/items/2": constants.RoleAdmin,
+	}
+	cases := make([]routeCase, len(routeCases))
+	for i, tc := range routeCases {
+		if role, ok := overrides[tc.method+" "+tc.path]; ok {
+			tc.role = role
+		}
+		cases[i] = tc
+	}
+
+	handler, keys := testServer(t, policy, true)
+	checkAccess(t, handler, keys, true, cases)
+}
+
+func TestRouteAccessInvalidKey(t *testing.T) {
+	handler, _ := testServer(t, auth.DefaultPolicy(), true)
+	for _, key := range []string{constants.APIKeyPrefix + "unknown", "not-a-key"} {
+		rec := serve(handler, http.MethodGet, "/api/songs", key)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("key %q: status = %d, want %d", key, rec.Code, http.StatusUnauthorized)
+		}
+		if !strings.Contains(rec.Header().Get(constants.HeaderWWWAuthenticate), constants.AuthSchemeBearer) {
+			t.Errorf("key %q: WWW-Authenticate = %q", key, rec.Header().Get(constants.HeaderWWWAuthenticate))
+		}
+	}
+}
+
+// Неизвестные пути и методы API получают problem+json со стабильным кодом
+func TestRouteNotFound(t *testing.T) {
+	handler, keys := testServer(t, auth.DefaultPolicy(), true)
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantCode   string
+		wantAllow  string
+	}{
+		{http.MethodPost, "/api/songs/1", http.StatusMethodNotAllowed, constants.CodeMethodNotAllowed, "GET, HEAD, PUT, PATCH, DELETE"},
+		{http.MethodDelete, "/api/songs", http.StatusMethodNotAllowed, constants.CodeMethodNotAllowed, "GET, HEAD, POST"},
+		{http.MethodGet, "/api/unknown", http.StatusNotFound, constants.CodeNotFound, ""},
+		{http.MethodGet, "/api/songs/1/unknown", http.StatusNotFound, constants.CodeNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := serve(handler, tt.method, tt.path, keys[constants.RoleAdmin])
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get(constants.HeaderContentType); got != constants.ContentTypeProblem {
+				t.Errorf("Content-Type = %q, want %q", got, constants.ContentTypeProblem)
+			}
+			var p models.Problem
+			if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if p.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", p.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get(constants.HeaderAllow); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+// Лимит по IP действует до аутентификации и маршрутизации, поэтому подбор ключей
+// и запросы к несуществующим маршрутам тоже ограничиваются
+func TestRateLimitByIP(t *testing.T) {
+	handler, _, _ := testServerLimits(t, auth.DefaultPolicy(), false, config.RateLimitConfig{
+		IP: config.RateLimit{Rate: 0.001, Burst: 2},
+	})
+
+	if rec := serve(handler, http.MethodGet, "/api/songs", constants.APIKeyPrefix+"unknown"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := serve(handler, http.MethodGet, "/api/unknown", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	rec := serve(handler, http.MethodGet, "/api/songs", constants.APIKeyPrefix+"unknown")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if rec.Header().Get(constants.HeaderRetryAfter) == "" || rec.Header().Get(constants.HeaderRateLimitLimit) != "2" {
+		t.Errorf("headers = %v", rec.Header())
+	}
+}
+
+// Лимит для маршрута, которого нет среди зарегистрированных, - ошибка настройки
+func TestRateLimitCheckRoutes(t *testing.T) {
+	limit := config.RateLimit{Rate: 1, Burst: 1}
+	_, _, limits := testServerLimits(t, auth.DefaultPolicy(), true, config.RateLimitConfig{
+		Routes: map[string]config.RateLimit{"GET /api/songs/{id}": limit, "POST /api/songs/import": limit},
+	})
+	if err := limits.CheckRoutes(); err != nil {
+		t.Errorf("CheckRoutes() error = %v", err)
+	}
+
+	_, _, limits = testServerLimits(t, auth.DefaultPolicy(), true, config.RateLimitConfig{
+		Routes: map[string]config.RateLimit{"GET /api/songs": limit, "GET /api/song/{id}": limit},
+	})
+	var ierr *i18n.Error
+	if err := limits.CheckRoutes(); !errors.As(err, &ierr) || ierr.Key != constants.ErrUnknownLimitRoute {
+		t.Errorf("CheckRoutes() error = %v, want %s", err, constants.ErrUnknownLimitRoute)
+	}
+}
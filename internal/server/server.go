@@ -1,40 +1,88 @@
 package server
 
 import (
-	"fmt"
-	"log"
 	"net/http"
+	"song-library/internal/auth"
 	"song-library/internal/config"
 	"song-library/internal/constants"
 	"song-library/internal/db"
 	"song-library/internal/handlers"
+	"song-library/internal/i18n"
+	"song-library/internal/importer"
+	"song-library/internal/lyrics"
+	"song-library/internal/middleware"
 	"song-library/internal/repository"
 	"song-library/internal/routers"
+	"song-library/internal/songinfo"
 	"strings"
 	"time"
 )
 
-func Setup(cfg *config.Config, logger *log.Logger) (*http.Server, error) {
+func Setup(cfg *config.Config, logger *i18n.Logger) (*http.Server, error) {
 	database, err := db.NewDatabase(cfg.GetDBConnString())
 	if err != nil {
 		logger.Printf(constants.LogError, constants.ErrDBConnection, err)
-		return nil, fmt.Errorf(constants.ErrDBConnection, err)
+		return nil, i18n.Errorf(constants.ErrDBConnection, err)
 	}
 
-	songRepo, err := repository.NewSongRepository(database)
+	songRepo, verseRepo, err := NewSongRepositories(cfg, database)
 	if err != nil {
-		return nil, fmt.Errorf(constants.ErrFormat, constants.ErrSongRepoCreate, err)
+		return nil, err
+	}
+	if err := songRepo.EnsureUniqueKey(cfg.UniqueSongKey); err != nil {
+		return nil, i18n.Errorf(constants.ErrFormat, constants.ErrUniqueKeySetup, err)
 	}
 
-	verseRepo, err := repository.NewVerseRepository(database)
+	artistRepo, err := repository.NewArtistRepository(database)
+	if err != nil {
+		return nil, i18n.Errorf(constants.ErrFormat, constants.ErrCatalogRepoCreate, err)
+	}
+	albumRepo, err := repository.NewAlbumRepository(database)
 	if err != nil {
-		return nil, fmt.Errorf(constants.ErrFormat, constants.ErrVerseRepoCreate, err)
+		return nil, i18n.Errorf(constants.ErrFormat, constants.ErrCatalogRepoCreate, err)
+	}
+	genreRepo, err := repository.NewGenreRepository(database)
+	if err != nil {
+		return nil, i18n.Errorf(constants.ErrFormat, constants.ErrCatalogRepoCreate, err)
+	}
+
+	playlistRepo, err := repository.NewPlaylistRepository(database)
+	if err != nil {
+		return nil, i18n.Errorf(constants.ErrFormat, constants.ErrPlaylistRepoCreate, err)
+	}
+
+	apiKeyRepo, err := repository.NewAPIKeyRepository(database)
+	if err != nil {
+		return nil, i18n.Errorf(constants.ErrFormat, constants.ErrAPIKeyRepoCreate, err)
 	}
 
 	logger.Println(constants.LogReposInitialized)
 
-	songHandler := handlers.NewSongHandler(songRepo, logger, cfg.ServerAddress)
+	jwtVerifier, err := auth.NewJWTVerifier(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+	if jwtVerifier == nil {
+		logger.Println(constants.LogAuthDisabled)
+	}
+	policy, err := auth.LoadPolicy(cfg.Auth.PolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	authMiddleware := middleware.Auth(auth.NewAuthenticator(apiKeyRepo, jwtVerifier), cfg.Auth.PublicRead, logger)
+
+	var songInfo songinfo.SongInfoProvider
+	if cfg.SongInfo.BaseURL != "" {
+		songInfo = songinfo.NewHTTPProvider(cfg.SongInfo, logger)
+	} else {
+		logger.Println(constants.LogSongInfoDisabled)
+	}
+
+	songHandler := handlers.NewSongHandler(songRepo, logger, songInfo, cfg.RequireIfMatch)
 	verseHandler := handlers.NewVerseHandler(verseRepo, logger)
+	catalogHandler := handlers.NewCatalogHandler(artistRepo, albumRepo, genreRepo, logger)
+	importHandler := handlers.NewImportHandler(importer.NewImporter(songRepo, logger), logger)
+	playlistHandler := handlers.NewPlaylistHandler(playlistRepo, logger)
 
 	serverAddress := cfg.ServerAddress
 	if idx := strings.Index(serverAddress, "//"); idx != -1 {
@@ -42,11 +90,37 @@ func Setup(cfg *config.Config, logger *log.Logger) (*http.Server, error) {
 	}
 	logger.Printf(constants.LogServerSetupAddr, serverAddress)
 
+	limits := middleware.NewRateLimits(cfg.RateLimit, logger)
+	handler := routers.SetupRoutes(songHandler, verseHandler, catalogHandler, importHandler, playlistHandler,
+		authMiddleware, policy, limits, logger)
+	// лимит для маршрута, которого нет среди зарегистрированных, - ошибка настройки
+	if err := limits.CheckRoutes(); err != nil {
+		return nil, err
+	}
+
 	return &http.Server{
 		Addr:         serverAddress,
-		Handler:      routers.SetupRoutes(songHandler, verseHandler),
+		Handler:      handler,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}, nil
 }
+
+// NewSongRepositories создает репозитории песен и куплетов с настройками из cfg.
+// Используется сервером и подкомандами командной строки
+func NewSongRepositories(cfg *config.Config, database *db.Database) (*repository.SongRepository, *repository.VerseRepository, error) {
+	verseRepo, err := repository.NewVerseRepository(database)
+	if err != nil {
+		return nil, nil, i18n.Errorf(constants.ErrFormat, constants.ErrVerseRepoCreate, err)
+	}
+
+	songRepo, err := repository.NewSongRepository(database, verseRepo, repository.SongRepositoryOptions{
+		Splitter:       lyrics.Splitter{DetectChorus: cfg.Lyrics.DetectChorus},
+		FuzzyThreshold: cfg.Search.FuzzyThreshold,
+	})
+	if err != nil {
+		return nil, nil, i18n.Errorf(constants.ErrFormat, constants.ErrSongRepoCreate, err)
+	}
+	return songRepo, verseRepo, nil
+}
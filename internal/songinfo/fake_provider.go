@@ -0,0 +1,65 @@
+package songinfo
+
+import (
+	"context"
+	"sync"
+
+	"song-library/internal/models"
+)
+
+// FakeProvider - реализация провайдера в памяти для тестов и локальной разработки
+type FakeProvider struct {
+	mu    sync.Mutex
+	songs map[fakeKey]models.SongDetail
+	err   error
+	calls int
+}
+
+type fakeKey struct {
+	group string
+	song  string
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{songs: make(map[fakeKey]models.SongDetail)}
+}
+
+// Add регистрирует ответ для пары исполнитель/название
+func (f *FakeProvider) Add(group, song string, detail models.SongDetail) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.songs[fakeKey{group: group, song: song}] = detail
+}
+
+// FailWith заставляет провайдер возвращать указанную ошибку на каждый запрос
+func (f *FakeProvider) FailWith(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// Calls возвращает количество выполненных запросов
+func (f *FakeProvider) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *FakeProvider) GetSongInfo(ctx context.Context, group, song string) (*models.SongDetail, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, ErrTimeout
+	}
+
+	detail, ok := f.songs[fakeKey{group: group, song: song}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &detail, nil
+}
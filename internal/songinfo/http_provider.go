@@ -0,0 +1,139 @@
+package songinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"song-library/internal/config"
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+	"song-library/internal/models"
+)
+
+// HTTPProvider обращается к внешнему сервису по схеме GET {base}/info?group=...&song=...
+type HTTPProvider struct {
+	client     *http.Client
+	baseURL    string
+	retries    int
+	authHeader string
+	authToken  string
+	logger     *i18n.Logger
+}
+
+func NewHTTPProvider(cfg config.SongInfoConfig, logger *i18n.Logger) *HTTPProvider {
+	return &HTTPProvider{
+		client:     &http.Client{Timeout: cfg.Timeout},
+		baseURL:    cfg.BaseURL,
+		retries:    cfg.Retries,
+		authHeader: cfg.AuthHeader,
+		authToken:  cfg.AuthToken,
+		logger:     logger,
+	}
+}
+
+// GetSongInfo повторяет запрос при таймаутах и недоступности сервиса,
+// ответы 404 и некорректное тело возвращаются сразу
+func (p *HTTPProvider) GetSongInfo(ctx context.Context, group, song string) (*models.SongDetail, error) {
+	var lastErr error
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		if attempt > 0 {
+			p.logger.Printf(constants.LogSongInfoRetry, attempt, lastErr)
+			select {
+			case <-ctx.Done():
+				return nil, i18n.Errorf(constants.ErrWrapFormat, ErrTimeout, ctx.Err())
+			case <-time.After(constants.SongInfoRetryBackoff * time.Duration(attempt)):
+			}
+		}
+
+		detail, err := p.fetch(ctx, group, song)
+		if err == nil {
+			return detail, nil
+		}
+		if !errors.Is(err, ErrTimeout) && !errors.Is(err, ErrUnavailable) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (p *HTTPProvider) fetch(ctx context.Context, group, song string) (*models.SongDetail, error) {
+	endpoint, err := url.JoinPath(p.baseURL, constants.SongInfoPath)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set(constants.QueryParamGroup, group)
+	query.Set(constants.QueryParamSong, song)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.authToken != "" {
+		req.Header.Set(p.authHeader, p.authToken)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return nil, i18n.Errorf(constants.ErrWrapFormat, ErrTimeout, err)
+		}
+		return nil, i18n.Errorf(constants.ErrWrapFormat, ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, i18n.Errorf(constants.ErrWrapFormat, ErrUnavailable, fmt.Sprintf(constants.ErrSongInfoStatus, resp.StatusCode))
+	case resp.StatusCode != http.StatusOK:
+		return nil, i18n.Errorf(constants.ErrWrapFormat, ErrMalformed, fmt.Sprintf(constants.ErrSongInfoStatus, resp.StatusCode))
+	}
+
+	var detail models.SongDetail
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		if isTimeout(err) {
+			return nil, i18n.Errorf(constants.ErrWrapFormat, ErrTimeout, err)
+		}
+		return nil, i18n.Errorf(constants.ErrWrapFormat, ErrMalformed, err)
+	}
+
+	releaseDate, err := normalizeReleaseDate(detail.ReleaseDate)
+	if err != nil {
+		return nil, i18n.Errorf(constants.ErrWrapFormat, ErrMalformed, err)
+	}
+	detail.ReleaseDate = releaseDate
+
+	return &detail, nil
+}
+
+// normalizeReleaseDate приводит дату из формата сервиса (16.07.2006) к формату БД
+func normalizeReleaseDate(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if date, err := time.Parse(constants.SongInfoDateFormat, value); err == nil {
+		return date.Format(constants.DateFormat), nil
+	}
+	date, err := time.Parse(constants.DateFormat, value)
+	if err != nil {
+		return "", err
+	}
+	return date.Format(constants.DateFormat), nil
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,188 @@
+package songinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"song-library/internal/config"
+	"song-library/internal/constants"
+	"song-library/internal/i18n"
+)
+
+// newTestProvider запускает сервис, отвечающий handler, и провайдер, обращающийся к нему.
+// Возвращает провайдер и счетчик запросов к сервису
+func newTestProvider(t *testing.T, retries int, handler func(w http.ResponseWriter, r *http.Request, call int32)) (*HTTPProvider, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, atomic.AddInt32(&calls, 1))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewHTTPProvider(config.SongInfoConfig{
+		BaseURL:    server.URL,
+		Timeout:    100 * time.Millisecond,
+		Retries:    retries,
+		AuthHeader: "X-Auth",
+		AuthToken:  "secret",
+	}, i18n.NewLogger(log.New(io.Discard, "", 0)))
+	return provider, &calls
+}
+
+func TestHTTPProviderSuccess(t *testing.T) {
+	provider, calls := newTestProvider(t, 2, func(w http.ResponseWriter, r *http.Request, _ int32) {
+		if r.URL.Path != "/"+constants.SongInfoPath {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get(constants.QueryParamGroup); got != "Muse" {
+			t.Errorf("group = %q", got)
+		}
+		if got := r.URL.Query().Get(constants.QueryParamSong); got != "Supermassive Black Hole" {
+			t.Errorf("song = %q", got)
+		}
+		if got := r.Header.Get("X-Auth"); got != "secret" {
+			t.Errorf("auth header = %q", got)
+		}
+		fmt.Fprint(w, `{"releaseDate":"16.07.2006","text":"Ooh baby","link":"https://example.com"}`)
+	})
+
+	detail, err := provider.GetSongInfo(context.Background(), "Muse", "Supermassive Black Hole")
+	if err != nil {
+		t.Fatalf("GetSongInfo() error = %v", err)
+	}
+	if detail.ReleaseDate != "2006-07-16" || detail.Text != "Ooh baby" || detail.Link != "https://example.com" {
+		t.Errorf("detail = %+v", detail)
+	}
+	if *calls != 1 {
+		t.Errorf("calls = %d, want 1", *calls)
+	}
+}
+
+func TestHTTPProviderErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		retries   int
+		handler   func(w http.ResponseWriter, r *http.Request, call int32)
+		wantErr   error
+		wantCalls int32
+	}{
+		{
+			name:    "повтор после 5xx",
+			retries: 2,
+			handler: func(w http.ResponseWriter, _ *http.Request, call int32) {
+				if call < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				fmt.Fprint(w, `{"releaseDate":"2006-07-16"}`)
+			},
+			wantCalls: 3,
+		},
+		{
+			name:    "5xx после всех повторов",
+			retries: 1,
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			wantErr:   ErrUnavailable,
+			wantCalls: 2,
+		},
+		{
+			name:    "повтор после таймаута",
+			retries: 1,
+			handler: func(w http.ResponseWriter, _ *http.Request, call int32) {
+				if call == 1 {
+					time.Sleep(300 * time.Millisecond)
+				}
+				fmt.Fprint(w, `{}`)
+			},
+			wantCalls: 2,
+		},
+		{
+			name:    "таймаут после всех повторов",
+			retries: 1,
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				time.Sleep(300 * time.Millisecond)
+			},
+			wantErr:   ErrTimeout,
+			wantCalls: 2,
+		},
+		{
+			name:    "404 без повторов",
+			retries: 2,
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantErr:   ErrNotFound,
+			wantCalls: 1,
+		},
+		{
+			name:    "некорректное тело без повторов",
+			retries: 2,
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				fmt.Fprint(w, `{"releaseDate":`)
+			},
+			wantErr:   ErrMalformed,
+			wantCalls: 1,
+		},
+		{
+			name:    "некорректная дата без повторов",
+			retries: 2,
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				fmt.Fprint(w, `{"releaseDate":"вчера"}`)
+			},
+			wantErr:   ErrMalformed,
+			wantCalls: 1,
+		},
+		{
+			name:    "неожиданный статус 4xx без повторов",
+			retries: 2,
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				w.WriteHeader(http.StatusTeapot)
+			},
+			wantErr:   ErrMalformed,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, calls := newTestProvider(t, tt.retries, tt.handler)
+
+			_, err := provider.GetSongInfo(context.Background(), "Muse", "Uprising")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("GetSongInfo() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetSongInfo() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestHTTPProviderCanceledContext(t *testing.T) {
+	provider, calls := newTestProvider(t, 3, func(w http.ResponseWriter, _ *http.Request, _ int32) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := provider.GetSongInfo(ctx, "Muse", "Uprising")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("GetSongInfo() error = %v, want %v", err, ErrTimeout)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
@@ -0,0 +1,22 @@
+package songinfo
+
+import (
+	"context"
+	"errors"
+
+	"song-library/internal/constants"
+	"song-library/internal/models"
+)
+
+// Ошибки обогащения позволяют обработчику различать причины сбоя
+var (
+	ErrNotFound    = errors.New(constants.ErrSongInfoNotFound)
+	ErrTimeout     = errors.New(constants.ErrSongInfoTimeout)
+	ErrMalformed   = errors.New(constants.ErrSongInfoMalformed)
+	ErrUnavailable = errors.New(constants.ErrSongInfoUnavailable)
+)
+
+// SongInfoProvider получает дополнительную информацию о песне по исполнителю и названию
+type SongInfoProvider interface {
+	GetSongInfo(ctx context.Context, group, song string) (*models.SongDetail, error)
+}
@@ -0,0 +1,96 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrillicToLatin - упрощенная транслитерация, близкая к тому, как пользователи
+// набирают русские названия латиницей. SQL-функция artist_key (миграция 015)
+// повторяет эту таблицу, при изменении их нужно менять вместе (совпадение
+// проверяет TestArtistKeyMatchesToLatin)
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// latinToCyrillic упорядочен от длинных сочетаний к коротким, чтобы "shch"
+// не разбиралось как "s" + "h" + ...
+var latinToCyrillic = []struct {
+	latin    string
+	cyrillic string
+}{
+	{"shch", "щ"}, {"sch", "щ"}, {"zh", "ж"}, {"kh", "х"}, {"ts", "ц"},
+	{"ch", "ч"}, {"sh", "ш"}, {"yu", "ю"}, {"ya", "я"}, {"yo", "ё"},
+	{"ye", "е"}, {"a", "а"}, {"b", "б"}, {"v", "в"}, {"g", "г"}, {"d", "д"},
+	{"e", "е"}, {"z", "з"}, {"i", "и"}, {"y", "ы"}, {"k", "к"}, {"l", "л"},
+	{"m", "м"}, {"n", "н"}, {"o", "о"}, {"p", "п"}, {"r", "р"}, {"s", "с"},
+	{"t", "т"}, {"u", "у"}, {"f", "ф"}, {"h", "х"}, {"c", "к"}, {"w", "в"},
+	{"q", "к"}, {"j", "й"}, {"x", "кс"},
+}
+
+// ToLatin переводит кириллицу в латиницу, остальные символы не меняются
+func ToLatin(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ToCyrillic переводит латиницу в кириллицу, остальные символы не меняются
+func ToCyrillic(s string) string {
+	s = strings.ToLower(s)
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		matched := false
+		for _, pair := range latinToCyrillic {
+			if strings.HasPrefix(s[i:], pair.latin) {
+				b.WriteString(pair.cyrillic)
+				i += len(pair.latin)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			r := []rune(s[i:])[0]
+			b.WriteRune(r)
+			i += len(string(r))
+		}
+	}
+	return b.String()
+}
+
+// Variant возвращает написание строки в другом алфавите: кириллицу переводит
+// в латиницу и наоборот. Пустая строка означает, что варианта нет
+func Variant(s string) string {
+	var variant string
+	switch {
+	case containsScript(s, unicode.Cyrillic):
+		variant = ToLatin(s)
+	case containsScript(s, unicode.Latin):
+		variant = ToCyrillic(s)
+	default:
+		return ""
+	}
+	if variant == strings.ToLower(s) {
+		return ""
+	}
+	return variant
+}
+
+func containsScript(s string, script *unicode.RangeTable) bool {
+	for _, r := range s {
+		if unicode.Is(script, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package translit
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestToLatin(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Кино", "kino"},
+		{"Щербаков", "shcherbakov"},
+		{"Жуки", "zhuki"},
+		{"Чайф", "chayf"},
+		{"Ёлка", "elka"},
+		{"Пьяный Ёжик", "pyanyy ezhik"},
+		{"Съешь", "sesh"},
+		{"Muse", "muse"},
+		{"Кино 2.0", "kino 2.0"},
+	}
+	for _, tt := range tests {
+		if got := ToLatin(tt.in); got != tt.want {
+			t.Errorf("ToLatin(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToCyrillic(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Kino", "кино"},
+		{"Shcherbakov", "щербаков"},
+		{"Schastye", "щасте"},
+		{"Zhuki", "жуки"},
+		{"Chayf", "чаыф"},
+		{"Max", "макс"},
+		{"Кино", "кино"},
+	}
+	for _, tt := range tests {
+		if got := ToCyrillic(tt.in); got != tt.want {
+			t.Errorf("ToCyrillic(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVariant(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Кино", "kino"},
+		{"Kino", "кино"},
+		{"2024", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Variant(tt.in); got != tt.want {
+			t.Errorf("Variant(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// sqlArtistKey вычисляет ключ так же, как SQL-функция artist_key из миграции:
+// LOWER(TRIM(name)), затем replace для сочетаний в порядке вложенности
+// и translate для одиночных букв (буквы без пары удаляются)
+func sqlArtistKey(t *testing.T) func(string) string {
+	t.Helper()
+	data, err := os.ReadFile("../../migrations/015_artist_translit_key_up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	body := string(data)
+	start := strings.Index(body, "FUNCTION artist_key")
+	end := strings.Index(body, "$$ LANGUAGE")
+	if start < 0 || end < start {
+		t.Fatal("artist_key not found in migration")
+	}
+
+	var literals []string
+	for _, m := range regexp.MustCompile(`'([^']*)'`).FindAllStringSubmatch(body[start:end], -1) {
+		literals = append(literals, m[1])
+	}
+	if len(literals) < 2 || len(literals)%2 != 0 {
+		t.Fatalf("unexpected artist_key literals %q", literals)
+	}
+	replaces := literals[:len(literals)-2]
+	from, to := []rune(literals[len(literals)-2]), []rune(literals[len(literals)-1])
+
+	return func(name string) string {
+		key := strings.ToLower(strings.Trim(name, " "))
+		for i := 0; i < len(replaces); i += 2 {
+			key = strings.ReplaceAll(key, replaces[i], replaces[i+1])
+		}
+		var b strings.Builder
+		for _, r := range key {
+			i := slices.Index(from, r)
+			switch {
+			case i < 0:
+				b.WriteRune(r)
+			case i < len(to):
+				b.WriteRune(to[i])
+			}
+		}
+		return b.String()
+	}
+}
+
+// Таблица ToLatin и SQL-функция artist_key должны переводить буквы одинаково,
+// иначе поиск с транслитерацией и объединение исполнителей разойдутся
+func TestArtistKeyMatchesToLatin(t *testing.T) {
+	artistKey := sqlArtistKey(t)
+
+	for r := range cyrillicToLatin {
+		for _, letter := range []string{string(r), strings.ToUpper(string(r))} {
+			if got, want := artistKey(letter), ToLatin(letter); got != want {
+				t.Errorf("artist_key(%q) = %q, ToLatin = %q", letter, got, want)
+			}
+		}
+	}
+
+	// одно и то же имя кириллицей и латиницей дает один ключ
+	for _, names := range [][]string{
+		{"Кино", "Kino", " КИНО "},
+		{"Ария", "Ariya"},
+		{"Чайф", "Chayf"},
+		{"Щербаков", "Shcherbakov"},
+		{"Сплин", "SPLIN"},
+		{"Земфира", "Zemfira"},
+	} {
+		want := artistKey(names[0])
+		for _, name := range names {
+			if got := artistKey(name); got != want {
+				t.Errorf("artist_key(%q) = %q, artist_key(%q) = %q", name, got, names[0], want)
+			}
+			if got := ToLatin(strings.Trim(name, " ")); got != want {
+				t.Errorf("ToLatin(%q) = %q, artist_key = %q", name, got, want)
+			}
+		}
+	}
+}
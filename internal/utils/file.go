@@ -1,10 +1,10 @@
 package utils
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"song-library/internal/constants"
+	"song-library/internal/i18n"
 )
 
 func ReadQueryFile(dir, filename string) (string, error) {
@@ -12,7 +12,7 @@ func ReadQueryFile(dir, filename string) (string, error) {
 	fullPath := filepath.Join(projectRoot, dir, filename)
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
-		return "", fmt.Errorf(constants.ErrReadingQueryFile, filename, err)
+		return "", i18n.Errorf(constants.ErrReadingQueryFile, filename, err)
 	}
 	return string(content), nil
 }
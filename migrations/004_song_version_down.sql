@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS increment_songs_version ON songs;
+DROP FUNCTION IF EXISTS increment_version_column();
+ALTER TABLE IF EXISTS songs DROP COLUMN IF EXISTS version;
@@ -0,0 +1,16 @@
+-- Версия песни для оптимистичной блокировки (ETag / If-Match)
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- Версия увеличивается при каждом изменении строки
+CREATE OR REPLACE FUNCTION increment_version_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER increment_songs_version
+    BEFORE UPDATE ON songs
+    FOR EACH ROW
+    EXECUTE FUNCTION increment_version_column();
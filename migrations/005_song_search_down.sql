@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_verses_search_vector;
+DROP INDEX IF EXISTS idx_songs_search_vector;
+ALTER TABLE IF EXISTS verses DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE IF EXISTS songs DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,18 @@
+-- Полнотекстовый поиск по текстам песен и куплетам.
+-- Каталог в основном русскоязычный, поэтому векторы строятся сразу
+-- в русской и английской конфигурациях
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('russian'::regconfig, coalesce(text, '')) ||
+        to_tsvector('english'::regconfig, coalesce(text, ''))
+    ) STORED;
+
+ALTER TABLE verses ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('russian'::regconfig, content) ||
+        to_tsvector('english'::regconfig, content)
+    ) STORED;
+
+-- GIN индексы для запросов вида: WHERE search_vector @@ query
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_verses_search_vector ON verses USING GIN (search_vector);
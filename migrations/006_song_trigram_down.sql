@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_songs_album_trgm;
+DROP INDEX IF EXISTS idx_songs_artist_trgm;
+DROP INDEX IF EXISTS idx_songs_title_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
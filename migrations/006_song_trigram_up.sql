@@ -0,0 +1,7 @@
+-- Нечеткий поиск с учетом опечаток по названию, исполнителю и альбому
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Триграммные индексы для запросов вида: WHERE 'кина' <% artist
+CREATE INDEX IF NOT EXISTS idx_songs_title_trgm ON songs USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_songs_artist_trgm ON songs USING GIN (artist gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_songs_album_trgm ON songs USING GIN (album gin_trgm_ops);
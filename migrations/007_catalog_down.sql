@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS sync_songs_main_artist ON songs;
+DROP TRIGGER IF EXISTS sync_songs_catalog ON songs;
+DROP FUNCTION IF EXISTS sync_song_main_artist();
+DROP FUNCTION IF EXISTS sync_song_catalog();
+DROP INDEX IF EXISTS idx_songs_genre_id;
+DROP INDEX IF EXISTS idx_songs_album_id;
+ALTER TABLE IF EXISTS songs DROP COLUMN IF EXISTS disc_number;
+ALTER TABLE IF EXISTS songs DROP COLUMN IF EXISTS track_number;
+ALTER TABLE IF EXISTS songs DROP COLUMN IF EXISTS genre_id;
+ALTER TABLE IF EXISTS songs DROP COLUMN IF EXISTS album_id;
+DROP TABLE IF EXISTS song_artists;
+DROP TABLE IF EXISTS albums;
+DROP TABLE IF EXISTS genres;
+DROP TABLE IF EXISTS artists;
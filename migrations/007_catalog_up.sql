@@ -0,0 +1,173 @@
+-- Исполнители, альбомы и жанры как отдельные сущности.
+-- Текстовые колонки songs.artist, album и genre остаются источником данных для API,
+-- триггеры ниже поддерживают справочники в согласованном состоянии.
+-- Записи сопоставляются по LOWER(TRIM(...)), поэтому "Кино" и "КИНО" - один исполнитель
+
+CREATE TABLE IF NOT EXISTS artists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS genres (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    normalized_name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Альбом принадлежит основному исполнителю песен, обложка и год хранятся один раз
+CREATE TABLE IF NOT EXISTS albums (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    normalized_title VARCHAR(255) NOT NULL,
+    artist_id INTEGER NOT NULL,
+    release_year INTEGER,
+    cover_url VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_albums_artist
+        FOREIGN KEY (artist_id)
+        REFERENCES artists(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_album_per_artist
+        UNIQUE(artist_id, normalized_title)
+);
+
+CREATE TRIGGER update_albums_updated_at
+    BEFORE UPDATE ON albums
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Участие исполнителей в песне: основной исполнитель (main) и приглашенные (featuring)
+CREATE TABLE IF NOT EXISTS song_artists (
+    song_id INTEGER NOT NULL,
+    artist_id INTEGER NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'main',
+    position INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_song_artists_song
+        FOREIGN KEY (song_id)
+        REFERENCES songs(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_song_artists_artist
+        FOREIGN KEY (artist_id)
+        REFERENCES artists(id)
+        ON DELETE CASCADE,
+    CONSTRAINT song_artists_role_check
+        CHECK (role IN ('main', 'featuring')),
+    PRIMARY KEY (song_id, artist_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_artists_artist_id ON song_artists(artist_id);
+
+-- Ссылки песни на справочники и положение в альбоме
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS album_id INTEGER REFERENCES albums(id) ON DELETE SET NULL;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS genre_id INTEGER REFERENCES genres(id) ON DELETE SET NULL;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS track_number INTEGER CHECK (track_number > 0);
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS disc_number INTEGER CHECK (disc_number > 0);
+
+CREATE INDEX IF NOT EXISTS idx_songs_album_id ON songs(album_id);
+CREATE INDEX IF NOT EXISTS idx_songs_genre_id ON songs(genre_id);
+
+-- Заполняем справочники из существующих песен
+INSERT INTO artists (name, normalized_name)
+SELECT DISTINCT ON (LOWER(TRIM(artist))) TRIM(artist), LOWER(TRIM(artist))
+FROM songs
+ORDER BY LOWER(TRIM(artist)), id
+ON CONFLICT (normalized_name) DO NOTHING;
+
+INSERT INTO genres (name, normalized_name)
+SELECT DISTINCT ON (LOWER(TRIM(genre))) TRIM(genre), LOWER(TRIM(genre))
+FROM songs
+WHERE COALESCE(TRIM(genre), '') <> ''
+ORDER BY LOWER(TRIM(genre)), id
+ON CONFLICT (normalized_name) DO NOTHING;
+
+INSERT INTO albums (title, normalized_title, artist_id, release_year)
+SELECT DISTINCT ON (a.id, LOWER(TRIM(s.album)))
+    TRIM(s.album), LOWER(TRIM(s.album)), a.id, EXTRACT(YEAR FROM s.release_date)::int
+FROM songs s
+JOIN artists a ON a.normalized_name = LOWER(TRIM(s.artist))
+WHERE COALESCE(TRIM(s.album), '') <> ''
+ORDER BY a.id, LOWER(TRIM(s.album)), s.release_date NULLS LAST, s.id
+ON CONFLICT (artist_id, normalized_title) DO NOTHING;
+
+INSERT INTO song_artists (song_id, artist_id, role, position)
+SELECT s.id, a.id, 'main', 0
+FROM songs s
+JOIN artists a ON a.normalized_name = LOWER(TRIM(s.artist))
+ON CONFLICT (song_id, artist_id) DO NOTHING;
+
+-- Проставление ссылок не должно менять updated_at и version песен
+ALTER TABLE songs DISABLE TRIGGER USER;
+
+UPDATE songs s
+SET album_id = al.id
+FROM artists a, albums al
+WHERE a.normalized_name = LOWER(TRIM(s.artist))
+    AND al.artist_id = a.id
+    AND al.normalized_title = LOWER(TRIM(s.album));
+
+UPDATE songs s
+SET genre_id = g.id
+FROM genres g
+WHERE g.normalized_name = LOWER(TRIM(s.genre));
+
+ALTER TABLE songs ENABLE TRIGGER USER;
+
+-- Справочные записи создаются и связываются при вставке и изменении песни
+CREATE OR REPLACE FUNCTION sync_song_catalog()
+RETURNS TRIGGER AS $$
+DECLARE
+    main_artist_id INTEGER;
+BEGIN
+    INSERT INTO artists (name, normalized_name)
+    VALUES (TRIM(NEW.artist), LOWER(TRIM(NEW.artist)))
+    ON CONFLICT (normalized_name) DO NOTHING;
+    SELECT id INTO main_artist_id FROM artists WHERE normalized_name = LOWER(TRIM(NEW.artist));
+
+    IF COALESCE(TRIM(NEW.album), '') = '' THEN
+        NEW.album_id = NULL;
+    ELSE
+        INSERT INTO albums (title, normalized_title, artist_id, release_year)
+        VALUES (TRIM(NEW.album), LOWER(TRIM(NEW.album)), main_artist_id, EXTRACT(YEAR FROM NEW.release_date)::int)
+        ON CONFLICT (artist_id, normalized_title) DO NOTHING;
+        SELECT id INTO NEW.album_id FROM albums
+        WHERE artist_id = main_artist_id AND normalized_title = LOWER(TRIM(NEW.album));
+    END IF;
+
+    IF COALESCE(TRIM(NEW.genre), '') = '' THEN
+        NEW.genre_id = NULL;
+    ELSE
+        INSERT INTO genres (name, normalized_name)
+        VALUES (TRIM(NEW.genre), LOWER(TRIM(NEW.genre)))
+        ON CONFLICT (normalized_name) DO NOTHING;
+        SELECT id INTO NEW.genre_id FROM genres WHERE normalized_name = LOWER(TRIM(NEW.genre));
+    END IF;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER sync_songs_catalog
+    BEFORE INSERT OR UPDATE OF artist, album, genre ON songs
+    FOR EACH ROW
+    EXECUTE FUNCTION sync_song_catalog();
+
+-- Основной исполнитель в song_artists следует за songs.artist
+CREATE OR REPLACE FUNCTION sync_song_main_artist()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM song_artists WHERE song_id = NEW.id AND role = 'main';
+    INSERT INTO song_artists (song_id, artist_id, role, position)
+    SELECT NEW.id, id, 'main', 0 FROM artists WHERE normalized_name = LOWER(TRIM(NEW.artist))
+    ON CONFLICT (song_id, artist_id) DO UPDATE SET role = 'main', position = 0;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER sync_songs_main_artist
+    AFTER INSERT OR UPDATE OF artist ON songs
+    FOR EACH ROW
+    EXECUTE FUNCTION sync_song_main_artist();
@@ -0,0 +1,5 @@
+ALTER TABLE verses
+    DROP CONSTRAINT IF EXISTS verses_end_ms_check,
+    DROP CONSTRAINT IF EXISTS verses_start_ms_check,
+    DROP COLUMN IF EXISTS end_ms,
+    DROP COLUMN IF EXISTS start_ms;
@@ -0,0 +1,8 @@
+-- Время начала и окончания куплета в миллисекундах от начала песни
+-- для синхронизированного текста (LRC, SRT). Порядок меток и их соответствие
+-- длительности песни проверяются приложением
+ALTER TABLE verses
+    ADD COLUMN start_ms INTEGER,
+    ADD COLUMN end_ms INTEGER,
+    ADD CONSTRAINT verses_start_ms_check CHECK (start_ms >= 0),
+    ADD CONSTRAINT verses_end_ms_check CHECK (end_ms > start_ms);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS ux_songs_normalized_key;
+DROP INDEX IF EXISTS idx_songs_normalized_key;
+ALTER TABLE songs DROP COLUMN IF EXISTS normalized_key;
+DROP FUNCTION IF EXISTS normalize_song_text(TEXT);
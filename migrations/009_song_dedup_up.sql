@@ -0,0 +1,17 @@
+-- Нормализация названия и исполнителя для поиска дубликатов: регистр, ё/е,
+-- знаки препинания (включая типографские кавычки и тире) и повторяющиеся
+-- пробелы не различаются
+CREATE OR REPLACE FUNCTION normalize_song_text(value TEXT)
+RETURNS TEXT AS $$
+    SELECT btrim(regexp_replace(
+        regexp_replace(translate(lower(value), 'Ёё', 'ее'), '[[:punct:]«»„“”‘’—–…]+', '', 'g'),
+        '[[:space:]]+', ' ', 'g'))
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Ключ дубликата "название - исполнитель". Дефисы удаляются из частей ключа,
+-- поэтому разделитель однозначен
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS normalized_key TEXT
+    GENERATED ALWAYS AS (normalize_song_text(title) || ' - ' || normalize_song_text(artist)) STORED;
+
+-- Уникальный индекс ux_songs_normalized_key создается при запуске, если включен SONG_UNIQUE_KEY
+CREATE INDEX IF NOT EXISTS idx_songs_normalized_key ON songs(normalized_key);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS ux_songs_normalized_key;
+DROP INDEX IF EXISTS idx_songs_deleted_at;
+-- Без колонки песни из корзины снова стали бы видны, поэтому удаляем их
+DELETE FROM songs WHERE deleted_at IS NOT NULL;
+ALTER TABLE songs DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,8 @@
+-- Удаленные песни попадают в корзину: deleted_at задает момент удаления,
+-- окончательно они удаляются фоновой очисткой вместе с куплетами
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Уникальный ключ пересоздается при запуске как частичный, только для песен вне корзины
+DROP INDEX IF EXISTS ux_songs_normalized_key;
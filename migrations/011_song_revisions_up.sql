@@ -0,0 +1,77 @@
+-- История изменений песен: каждая вставка и изменение строки songs сохраняет снимки
+-- до и после изменения. Автора и ID запроса приложение передает в настройках
+-- транзакции app.actor и app.request_id (set_audit.sql)
+CREATE TABLE IF NOT EXISTS song_revisions (
+    id SERIAL PRIMARY KEY,
+    song_id INTEGER NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    operation VARCHAR(16) NOT NULL,
+    actor VARCHAR(255),
+    request_id VARCHAR(128),
+    before JSONB,
+    after JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (song_id, revision)
+);
+
+-- Снимок содержит редактируемые поля песни с именами полей API
+CREATE OR REPLACE FUNCTION song_snapshot(s songs)
+RETURNS JSONB AS $$
+    SELECT jsonb_build_object(
+        'title', s.title,
+        'artist', s.artist,
+        'album', s.album,
+        'genre', s.genre,
+        'duration', s.duration,
+        'releaseDate', s.release_date,
+        'text', s.text,
+        'link', s.link,
+        'trackNumber', s.track_number,
+        'discNumber', s.disc_number,
+        'version', s.version
+    );
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION record_song_revision()
+RETURNS TRIGGER AS $$
+DECLARE
+    revision_operation TEXT := 'update';
+    snapshot_before JSONB;
+    snapshot_after JSONB := song_snapshot(NEW);
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        revision_operation := 'create';
+    ELSE
+        snapshot_before := song_snapshot(OLD);
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            revision_operation := 'delete';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            revision_operation := 'restore';
+        ELSIF snapshot_before - 'version' = snapshot_after - 'version' THEN
+            -- изменились только служебные колонки, ревизия не нужна
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    INSERT INTO song_revisions (song_id, revision, operation, actor, request_id, before, after)
+    SELECT NEW.id, COALESCE(MAX(revision), 0) + 1, revision_operation,
+        NULLIF(current_setting('app.actor', true), ''),
+        NULLIF(current_setting('app.request_id', true), ''),
+        snapshot_before, snapshot_after
+    FROM song_revisions
+    WHERE song_id = NEW.id;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_songs_revision
+    AFTER INSERT OR UPDATE ON songs
+    FOR EACH ROW
+    EXECUTE FUNCTION record_song_revision();
+
+-- Уже существующие песни получают исходную ревизию, от которой считается история
+INSERT INTO song_revisions (song_id, revision, operation, after)
+SELECT s.id, 1, 'baseline', song_snapshot(s)
+FROM songs s
+ON CONFLICT (song_id, revision) DO NOTHING;
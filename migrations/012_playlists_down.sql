@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS renumber_playlist_items_after_delete ON playlist_items;
+DROP FUNCTION IF EXISTS renumber_playlist_items();
+DROP TABLE IF EXISTS playlist_items;
+DROP TABLE IF EXISTS playlists;
@@ -0,0 +1,53 @@
+-- Плейлисты пользователей
+CREATE TABLE IF NOT EXISTS playlists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_playlists_updated_at
+    BEFORE UPDATE ON playlists
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_playlists_name ON playlists(LOWER(name));
+
+-- Песни плейлиста. Позиции идут подряд с 1, одна песня может встречаться несколько раз.
+-- Уникальность позиции проверяется в конце команды, поэтому позиции можно
+-- сдвигать одним UPDATE без промежуточных отрицательных значений
+CREATE TABLE IF NOT EXISTS playlist_items (
+    id SERIAL PRIMARY KEY,
+    playlist_id INTEGER NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    song_id INTEGER NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_playlist_position
+        UNIQUE (playlist_id, position) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+CREATE INDEX idx_playlist_items_song_id ON playlist_items(song_id);
+
+-- Удаление элементов, в том числе каскадное при окончательном удалении песни
+-- из корзины, закрывает разрывы в нумерации затронутых плейлистов
+CREATE OR REPLACE FUNCTION renumber_playlist_items()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE playlist_items pi
+    SET position = o.position
+    FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY playlist_id ORDER BY position) AS position
+        FROM playlist_items
+        WHERE playlist_id IN (SELECT DISTINCT playlist_id FROM removed_items)
+    ) o
+    WHERE pi.id = o.id AND pi.position <> o.position;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER renumber_playlist_items_after_delete
+    AFTER DELETE ON playlist_items
+    REFERENCING OLD TABLE AS removed_items
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION renumber_playlist_items();
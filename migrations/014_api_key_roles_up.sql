@@ -0,0 +1,7 @@
+-- Роль API-ключа: viewer - чтение, editor - создание и изменение, admin - удаление и импорт.
+-- Ключи, созданные до появления ролей, сохраняют полный доступ
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'admin'
+    CHECK (role IN ('viewer', 'editor', 'admin'));
+
+ALTER TABLE api_keys ALTER COLUMN role SET DEFAULT 'viewer';
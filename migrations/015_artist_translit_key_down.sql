@@ -0,0 +1,48 @@
+-- Объединенные исполнители не разделяются обратно
+CREATE OR REPLACE FUNCTION sync_song_catalog()
+RETURNS TRIGGER AS $$
+DECLARE
+    main_artist_id INTEGER;
+BEGIN
+    INSERT INTO artists (name, normalized_name)
+    VALUES (TRIM(NEW.artist), LOWER(TRIM(NEW.artist)))
+    ON CONFLICT (normalized_name) DO NOTHING;
+    SELECT id INTO main_artist_id FROM artists WHERE normalized_name = LOWER(TRIM(NEW.artist));
+
+    IF COALESCE(TRIM(NEW.album), '') = '' THEN
+        NEW.album_id = NULL;
+    ELSE
+        INSERT INTO albums (title, normalized_title, artist_id, release_year)
+        VALUES (TRIM(NEW.album), LOWER(TRIM(NEW.album)), main_artist_id, EXTRACT(YEAR FROM NEW.release_date)::int)
+        ON CONFLICT (artist_id, normalized_title) DO NOTHING;
+        SELECT id INTO NEW.album_id FROM albums
+        WHERE artist_id = main_artist_id AND normalized_title = LOWER(TRIM(NEW.album));
+    END IF;
+
+    IF COALESCE(TRIM(NEW.genre), '') = '' THEN
+        NEW.genre_id = NULL;
+    ELSE
+        INSERT INTO genres (name, normalized_name)
+        VALUES (TRIM(NEW.genre), LOWER(TRIM(NEW.genre)))
+        ON CONFLICT (normalized_name) DO NOTHING;
+        SELECT id INTO NEW.genre_id FROM genres WHERE normalized_name = LOWER(TRIM(NEW.genre));
+    END IF;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION sync_song_main_artist()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM song_artists WHERE song_id = NEW.id AND role = 'main';
+    INSERT INTO song_artists (song_id, artist_id, role, position)
+    SELECT NEW.id, id, 'main', 0 FROM artists WHERE normalized_name = LOWER(TRIM(NEW.artist))
+    ON CONFLICT (song_id, artist_id) DO UPDATE SET role = 'main', position = 0;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+UPDATE artists SET normalized_name = LOWER(TRIM(name));
+
+DROP FUNCTION IF EXISTS artist_key(TEXT);
@@ -0,0 +1,107 @@
+-- Ключ исполнителя учитывает транслитерацию: "Кино", "КИНО" и "Kino" - один исполнитель.
+-- artist_key повторяет таблицу translit.ToLatin: кириллица переводится в латиницу,
+-- после чего имена сравниваются без учета регистра и крайних пробелов
+CREATE OR REPLACE FUNCTION artist_key(name TEXT)
+RETURNS TEXT AS $$
+    SELECT translate(
+        replace(replace(replace(replace(replace(replace(replace(replace(
+            LOWER(TRIM(name)),
+            'щ', 'shch'), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'),
+            'ч', 'ch'), 'ш', 'sh'), 'ю', 'yu'), 'я', 'ya'),
+        -- ъ и ь не имеют пары и удаляются
+        'абвгдеёзийклмнопрстуфыэъь',
+        'abvgdeeziyklmnoprstufye'
+    );
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Исполнители, ключи которых совпали, объединяются с самым ранним из них
+CREATE TEMP TABLE artist_merge ON COMMIT DROP AS
+SELECT id, keep_id
+FROM (
+    SELECT id, MIN(id) OVER (PARTITION BY artist_key(name)) AS keep_id
+    FROM artists
+) a
+WHERE id <> keep_id;
+
+INSERT INTO song_artists (song_id, artist_id, role, position)
+SELECT sa.song_id, m.keep_id, sa.role, sa.position
+FROM song_artists sa
+JOIN artist_merge m ON m.id = sa.artist_id
+ORDER BY sa.role = 'featuring', sa.position
+ON CONFLICT (song_id, artist_id) DO NOTHING;
+
+-- Альбомы с одинаковым названием сливаются в альбом основного исполнителя
+CREATE TEMP TABLE album_merge ON COMMIT DROP AS
+SELECT al.id,
+    COALESCE(m.keep_id, al.artist_id) AS artist_id,
+    FIRST_VALUE(al.id) OVER (
+        PARTITION BY COALESCE(m.keep_id, al.artist_id), al.normalized_title
+        ORDER BY m.id IS NOT NULL, al.id
+    ) AS keep_id
+FROM albums al
+LEFT JOIN artist_merge m ON m.id = al.artist_id;
+
+-- Перенос ссылок не должен менять updated_at и version песен
+ALTER TABLE songs DISABLE TRIGGER USER;
+
+UPDATE songs s
+SET album_id = am.keep_id
+FROM album_merge am
+WHERE s.album_id = am.id AND am.id <> am.keep_id;
+
+ALTER TABLE songs ENABLE TRIGGER USER;
+
+DELETE FROM albums WHERE id IN (SELECT id FROM album_merge WHERE id <> keep_id);
+
+UPDATE albums al
+SET artist_id = am.artist_id
+FROM album_merge am
+WHERE al.id = am.id AND al.artist_id <> am.artist_id;
+
+DELETE FROM artists WHERE id IN (SELECT id FROM artist_merge);
+
+UPDATE artists SET normalized_name = artist_key(name);
+
+CREATE OR REPLACE FUNCTION sync_song_catalog()
+RETURNS TRIGGER AS $$
+DECLARE
+    main_artist_id INTEGER;
+BEGIN
+    INSERT INTO artists (name, normalized_name)
+    VALUES (TRIM(NEW.artist), artist_key(NEW.artist))
+    ON CONFLICT (normalized_name) DO NOTHING;
+    SELECT id INTO main_artist_id FROM artists WHERE normalized_name = artist_key(NEW.artist);
+
+    IF COALESCE(TRIM(NEW.album), '') = '' THEN
+        NEW.album_id = NULL;
+    ELSE
+        INSERT INTO albums (title, normalized_title, artist_id, release_year)
+        VALUES (TRIM(NEW.album), LOWER(TRIM(NEW.album)), main_artist_id, EXTRACT(YEAR FROM NEW.release_date)::int)
+        ON CONFLICT (artist_id, normalized_title) DO NOTHING;
+        SELECT id INTO NEW.album_id FROM albums
+        WHERE artist_id = main_artist_id AND normalized_title = LOWER(TRIM(NEW.album));
+    END IF;
+
+    IF COALESCE(TRIM(NEW.genre), '') = '' THEN
+        NEW.genre_id = NULL;
+    ELSE
+        INSERT INTO genres (name, normalized_name)
+        VALUES (TRIM(NEW.genre), LOWER(TRIM(NEW.genre)))
+        ON CONFLICT (normalized_name) DO NOTHING;
+        SELECT id INTO NEW.genre_id FROM genres WHERE normalized_name = LOWER(TRIM(NEW.genre));
+    END IF;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION sync_song_main_artist()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM song_artists WHERE song_id = NEW.id AND role = 'main';
+    INSERT INTO song_artists (song_id, artist_id, role, position)
+    SELECT NEW.id, id, 'main', 0 FROM artists WHERE normalized_name = artist_key(NEW.artist)
+    ON CONFLICT (song_id, artist_id) DO UPDATE SET role = 'main', position = 0;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
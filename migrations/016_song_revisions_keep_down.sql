@@ -0,0 +1,46 @@
+-- История удаленных песен не восстанавливается вместе с внешним ключом и удаляется
+DELETE FROM song_revisions r
+WHERE NOT EXISTS (SELECT 1 FROM songs s WHERE s.id = r.song_id);
+
+ALTER TABLE song_revisions DROP CONSTRAINT IF EXISTS song_revisions_song_id_fkey;
+ALTER TABLE song_revisions ADD CONSTRAINT song_revisions_song_id_fkey
+    FOREIGN KEY (song_id) REFERENCES songs(id) ON DELETE CASCADE;
+
+DROP TRIGGER IF EXISTS record_songs_revision ON songs;
+CREATE OR REPLACE FUNCTION record_song_revision()
+RETURNS TRIGGER AS $$
+DECLARE
+    revision_operation TEXT := 'update';
+    snapshot_before JSONB;
+    snapshot_after JSONB := song_snapshot(NEW);
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        revision_operation := 'create';
+    ELSE
+        snapshot_before := song_snapshot(OLD);
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            revision_operation := 'delete';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            revision_operation := 'restore';
+        ELSIF snapshot_before - 'version' = snapshot_after - 'version' THEN
+            -- изменились только служебные колонки, ревизия не нужна
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    INSERT INTO song_revisions (song_id, revision, operation, actor, request_id, before, after)
+    SELECT NEW.id, COALESCE(MAX(revision), 0) + 1, revision_operation,
+        NULLIF(current_setting('app.actor', true), ''),
+        NULLIF(current_setting('app.request_id', true), ''),
+        snapshot_before, snapshot_after
+    FROM song_revisions
+    WHERE song_id = NEW.id;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_songs_revision
+    AFTER INSERT OR UPDATE ON songs
+    FOR EACH ROW
+    EXECUTE FUNCTION record_song_revision();
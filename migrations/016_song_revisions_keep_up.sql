@@ -0,0 +1,51 @@
+-- История изменений переживает окончательное удаление песни: внешний ключ
+-- с каскадным удалением снимается, song_id остается идентификатором удаленной песни
+ALTER TABLE song_revisions DROP CONSTRAINT IF EXISTS song_revisions_song_id_fkey;
+
+-- Окончательное удаление записывается последней ревизией со снимком удаленной песни.
+-- Операция берется из настройки транзакции app.delete_operation: merge при
+-- объединении дубликатов (set_delete_operation.sql), иначе purge - очистка корзины
+CREATE OR REPLACE FUNCTION record_song_revision()
+RETURNS TRIGGER AS $$
+DECLARE
+    revision_operation TEXT := 'update';
+    revision_song songs := NEW;
+    snapshot_before JSONB;
+    snapshot_after JSONB;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        revision_operation := 'create';
+    ELSIF TG_OP = 'DELETE' THEN
+        revision_operation := COALESCE(NULLIF(current_setting('app.delete_operation', true), ''), 'purge');
+        revision_song := OLD;
+        snapshot_before := song_snapshot(OLD);
+    ELSE
+        snapshot_before := song_snapshot(OLD);
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            revision_operation := 'delete';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            revision_operation := 'restore';
+        ELSIF snapshot_before - 'version' = song_snapshot(NEW) - 'version' THEN
+            -- изменились только служебные колонки, ревизия не нужна
+            RETURN NULL;
+        END IF;
+    END IF;
+    snapshot_after := song_snapshot(revision_song);
+
+    INSERT INTO song_revisions (song_id, revision, operation, actor, request_id, before, after)
+    SELECT revision_song.id, COALESCE(MAX(revision), 0) + 1, revision_operation,
+        NULLIF(current_setting('app.actor', true), ''),
+        NULLIF(current_setting('app.request_id', true), ''),
+        snapshot_before, snapshot_after
+    FROM song_revisions
+    WHERE song_id = revision_song.id;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS record_songs_revision ON songs;
+CREATE TRIGGER record_songs_revision
+    AFTER INSERT OR UPDATE OR DELETE ON songs
+    FOR EACH ROW
+    EXECUTE FUNCTION record_song_revision();